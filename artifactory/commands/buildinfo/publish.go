@@ -9,6 +9,7 @@ import (
 	"time"
 
 	buildinfo "github.com/jfrog/build-info-go/entities"
+	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -28,6 +29,9 @@ type BuildPublishCommand struct {
 	config             *biconf.Configuration
 	detailedSummary    bool
 	summary            *clientutils.Sha256Summary
+	// When true, the command's result is printed as a formats.BuildCommandOutput JSON.
+	structuredOutput bool
+	buildInfoUiUrl   string
 }
 
 func NewBuildPublishCommand() *BuildPublishCommand {
@@ -67,6 +71,11 @@ func (bpc *BuildPublishCommand) IsDetailedSummary() bool {
 	return bpc.detailedSummary
 }
 
+func (bpc *BuildPublishCommand) SetStructuredOutput(structuredOutput bool) *BuildPublishCommand {
+	bpc.structuredOutput = structuredOutput
+	return bpc
+}
+
 func (bpc *BuildPublishCommand) CommandName() string {
 	return "rt_build_publish"
 }
@@ -76,6 +85,15 @@ func (bpc *BuildPublishCommand) ServerDetails() (*config.ServerDetails, error) {
 }
 
 func (bpc *BuildPublishCommand) Run() error {
+	if !bpc.structuredOutput {
+		return bpc.publish()
+	}
+	startTime := time.Now()
+	err := bpc.publish()
+	return bpc.printCommandOutput(time.Since(startTime), err)
+}
+
+func (bpc *BuildPublishCommand) publish() error {
 	servicesManager, err := utils.CreateServiceManager(bpc.serverDetails, -1, 0, bpc.config.DryRun)
 	if err != nil {
 		return err
@@ -132,6 +150,7 @@ func (bpc *BuildPublishCommand) Run() error {
 	if err != nil {
 		return err
 	}
+	bpc.buildInfoUiUrl = buildLink
 
 	err = build.Clean()
 	if err != nil {
@@ -145,9 +164,36 @@ func (bpc *BuildPublishCommand) Run() error {
 	}
 
 	log.Info(logMsg)
+	if bpc.structuredOutput {
+		// The build info UI URL is printed as part of the command's output.
+		return nil
+	}
 	return logJsonOutput(buildLink)
 }
 
+// Prints the published build as JSON, and returns the publish's resulting error.
+func (bpc *BuildPublishCommand) printCommandOutput(duration time.Duration, runErr error) error {
+	buildName, err := bpc.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildNumber, err := bpc.buildConfiguration.GetBuildNumber()
+	if err != nil {
+		return err
+	}
+	item := formats.BuildOutputItem{
+		BuildName:      buildName,
+		BuildNumber:    buildNumber,
+		Project:        bpc.buildConfiguration.GetProject(),
+		BuildInfoUiUrl: bpc.buildInfoUiUrl,
+	}
+	output := commandsutils.NewBuildCommandOutput(bpc.CommandName(), bpc.serverDetails.ServerId, duration, []formats.BuildOutputItem{item}, runErr)
+	if err = commandsutils.PrintCommandOutput(output); runErr != nil {
+		return runErr
+	}
+	return err
+}
+
 func logJsonOutput(buildInfoUiUrl string) error {
 	output := formats.BuildPublishOutput{BuildInfoUiUrl: buildInfoUiUrl}
 	results, err := output.JSON()
@@ -47,6 +47,8 @@ func TestPrintBuildInfoLink(t *testing.T) {
 			nil,
 			true,
 			nil,
+			false,
+			"",
 		}
 		buildPubComService, err := buildPubConf.getBuildInfoUiUrl(linkType.majorVersion, linkType.buildTime)
 		assert.NoError(t, err)
@@ -0,0 +1,265 @@
+package generic
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const defaultCleanupBatchSize = 1000
+
+// An artifact which is deleted by a cleanup policy, and the rule which deletes it.
+type CleanupCandidate struct {
+	Rule     string
+	Artifact serviceutils.ResultItem
+}
+
+type cleanupReportRow struct {
+	Rule           string `col-name:"Rule"`
+	Path           string `col-name:"Path"`
+	Size           string `col-name:"Size"`
+	Created        string `col-name:"Created"`
+	LastDownloaded string `col-name:"Last Downloaded"`
+}
+
+// CleanupCommand deletes the artifacts which match the rules of a cleanup policy.
+// The rules are evaluated using AQL, and the artifacts to delete are printed in a report before they're deleted.
+// In dry-run mode, only the report is printed.
+type CleanupCommand struct {
+	GenericCommand
+	policyPath string
+	threads    int
+	batchSize  int
+	candidates []CleanupCandidate
+}
+
+func NewCleanupCommand() *CleanupCommand {
+	return &CleanupCommand{GenericCommand: *NewGenericCommand(), batchSize: defaultCleanupBatchSize}
+}
+
+// The path of the YAML cleanup policy. See CleanupPolicy for its format.
+func (cc *CleanupCommand) SetPolicyPath(policyPath string) *CleanupCommand {
+	cc.policyPath = policyPath
+	return cc
+}
+
+func (cc *CleanupCommand) Threads() int {
+	return cc.threads
+}
+
+func (cc *CleanupCommand) SetThreads(threads int) *CleanupCommand {
+	cc.threads = threads
+	return cc
+}
+
+// The number of artifacts deleted in each batch.
+func (cc *CleanupCommand) SetBatchSize(batchSize int) *CleanupCommand {
+	cc.batchSize = batchSize
+	return cc
+}
+
+// Returns the artifacts which were found by the last run.
+func (cc *CleanupCommand) Candidates() []CleanupCandidate {
+	return cc.candidates
+}
+
+func (cc *CleanupCommand) CommandName() string {
+	return "rt_cleanup"
+}
+
+func (cc *CleanupCommand) Run() (err error) {
+	if cc.batchSize < 1 {
+		return errorutils.CheckErrorf("the cleanup batch size must be positive, but %d was found", cc.batchSize)
+	}
+	policy, err := LoadCleanupPolicy(cc.policyPath)
+	if err != nil {
+		return
+	}
+	servicesManager, err := utils.CreateServiceManager(cc.serverDetails, cc.retries, cc.retryWaitTimeMilliSecs, false)
+	if err != nil {
+		return
+	}
+	cc.candidates, err = findCleanupCandidates(servicesManager, policy, time.Now())
+	if err != nil {
+		return
+	}
+	if err = printCleanupReport(cc.candidates); err != nil || cc.DryRun() || len(cc.candidates) == 0 {
+		return
+	}
+	if !cc.Quiet() && !coreutils.AskYesNo("Are you sure you want to delete the above artifacts?\n"+
+		"You can avoid this confirmation message by adding --quiet to the command.", false) {
+		return
+	}
+	return cc.deleteCandidates()
+}
+
+// The fields by which the artifacts which are referenced by a build, as its artifacts or dependencies, are found.
+var cleanupBuildReferenceFields = []string{"artifact.module.build.name", "dependency.module.build.name"}
+
+// Returns the artifacts which are deleted by the policy's rules. An artifact which matches several rules is attributed to the first of them.
+func findCleanupCandidates(servicesManager artifactory.ArtifactoryServicesManager, policy *CleanupPolicy, now time.Time) ([]CleanupCandidate, error) {
+	var candidates []CleanupCandidate
+	found := make(map[string]bool)
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		log.Info("Evaluating the", rule.Name, "cleanup rule...")
+		toDelete, err := evaluateCleanupRule(servicesManager, rule, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, artifact := range toDelete {
+			if !found[artifact.GetItemRelativePath()] {
+				found[artifact.GetItemRelativePath()] = true
+				candidates = append(candidates, CleanupCandidate{Rule: rule.Name, Artifact: artifact})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Artifact.GetItemRelativePath() < candidates[j].Artifact.GetItemRelativePath()
+	})
+	return candidates, nil
+}
+
+func evaluateCleanupRule(servicesManager artifactory.ArtifactoryServicesManager, rule *CleanupRule, now time.Time) (toDelete []serviceutils.ResultItem, err error) {
+	referencedPaths := make(map[string]bool)
+	for _, buildReferenceField := range cleanupBuildReferenceFields {
+		if err = searchReferencedPaths(servicesManager, createCleanupAqlQuery(rule, buildReferenceField), referencedPaths); err != nil {
+			return
+		}
+	}
+	artifactsReader, err := searchCleanupArtifacts(servicesManager, createCleanupAqlQuery(rule, ""))
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := artifactsReader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	return rule.evaluate(artifactsReader, referencedPaths, now)
+}
+
+// Returns an AQL query for the files in the rule's repositories and path.
+// If buildReferenceField is provided, only the files which are referenced by a build through this field are returned.
+func createCleanupAqlQuery(rule *CleanupRule, buildReferenceField string) string {
+	var repos []interface{}
+	for _, repo := range rule.Repos {
+		repos = append(repos, map[string]interface{}{"repo": map[string]string{"$match": repo}})
+	}
+	conditions := []interface{}{map[string]interface{}{"$or": repos}, map[string]string{"type": "file"}}
+	if rule.Path != "" {
+		conditions = append(conditions, map[string]interface{}{"path": map[string]string{"$match": rule.Path}})
+	}
+	if buildReferenceField != "" {
+		conditions = append(conditions, map[string]interface{}{buildReferenceField: map[string]string{"$match": "*"}})
+	}
+	// Marshaling maps of strings and slices can't fail.
+	criteria, _ := json.Marshal(map[string]interface{}{"$and": conditions})
+	if buildReferenceField != "" {
+		return `items.find(` + string(criteria) + `).include("repo","path","name")`
+	}
+	return `items.find(` + string(criteria) + `).include("repo","path","name","type","size","created","property","stat.downloaded")`
+}
+
+// Adds the paths of the artifacts found by the query to referencedPaths.
+func searchReferencedPaths(servicesManager artifactory.ArtifactoryServicesManager, query string, referencedPaths map[string]bool) (err error) {
+	reader, err := searchCleanupArtifacts(servicesManager, query)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	for artifact := new(serviceutils.ResultItem); reader.NextRecord(artifact) == nil; artifact = new(serviceutils.ResultItem) {
+		referencedPaths[artifact.GetItemRelativePath()] = true
+	}
+	return reader.GetError()
+}
+
+// The results are streamed into a file, since a rule may match all the artifacts in large repositories.
+func searchCleanupArtifacts(servicesManager artifactory.ArtifactoryServicesManager, query string) (*content.ContentReader, error) {
+	commonConf, err := serviceutils.NewCommonConfImpl(servicesManager.GetConfig().GetServiceDetails())
+	if err != nil {
+		return nil, err
+	}
+	return serviceutils.ExecAqlSaveToFile(query, commonConf)
+}
+
+func printCleanupReport(candidates []CleanupCandidate) error {
+	var rows []cleanupReportRow
+	var totalSize int64
+	for _, candidate := range candidates {
+		rows = append(rows, cleanupReportRow{
+			Rule:           candidate.Rule,
+			Path:           candidate.Artifact.GetItemRelativePath(),
+			Size:           utils.ConvertIntToStorageSizeString(candidate.Artifact.Size),
+			Created:        candidate.Artifact.Created,
+			LastDownloaded: getLastDownloaded(candidate.Artifact),
+		})
+		totalSize += candidate.Artifact.Size
+	}
+	if err := coreutils.PrintTable(rows, "Cleanup Report", "No artifacts to delete", false); err != nil {
+		return err
+	}
+	log.Info(len(candidates), "artifacts to delete, with a total size of", utils.ConvertIntToStorageSizeString(totalSize)+".")
+	return nil
+}
+
+// Deletes the candidates in batches, using the delete command. A failed batch doesn't stop the following batches.
+func (cc *CleanupCommand) deleteCandidates() (err error) {
+	deleteCommand := NewDeleteCommand().SetThreads(cc.threads)
+	deleteCommand.SetServerDetails(cc.serverDetails).SetRetries(cc.retries).SetRetryWaitMilliSecs(cc.retryWaitTimeMilliSecs)
+	var successCount, failCount int
+	for start := 0; start < len(cc.candidates); start += cc.batchSize {
+		end := start + cc.batchSize
+		if end > len(cc.candidates) {
+			end = len(cc.candidates)
+		}
+		log.Info("Deleting artifacts", start+1, "to", end, "of", strconv.Itoa(len(cc.candidates))+"...")
+		batchSuccess, batchErr := cc.deleteBatch(deleteCommand, cc.candidates[start:end])
+		successCount += batchSuccess
+		failCount += end - start - batchSuccess
+		if batchErr != nil {
+			log.Error(batchErr)
+			err = batchErr
+		}
+	}
+	cc.result.SetSuccessCount(successCount)
+	cc.result.SetFailCount(failCount)
+	return
+}
+
+func (cc *CleanupCommand) deleteBatch(deleteCommand *DeleteCommand, batch []CleanupCandidate) (successCount int, err error) {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return
+	}
+	for _, candidate := range batch {
+		writer.Write(candidate.Artifact)
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	successCount, _, err = deleteCommand.DeleteFiles(reader)
+	return
+}
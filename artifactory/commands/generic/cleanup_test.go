@@ -0,0 +1,190 @@
+package generic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCleanupPolicy = `rules:
+  - name: old-versions
+    repos: ["libs-*"]
+    keepLast: 1
+    olderThan: 30d
+    excludeProps: "retain=true"
+`
+
+func TestLoadCleanupPolicy(t *testing.T) {
+	policy, err := LoadCleanupPolicy(writeCleanupPolicy(t, testCleanupPolicy))
+	assert.NoError(t, err)
+	if assert.Len(t, policy.Rules, 1) {
+		assert.Equal(t, []string{"libs-*"}, policy.Rules[0].Repos)
+		assert.Equal(t, 30*24*time.Hour, policy.Rules[0].olderThan)
+	}
+
+	invalidPolicies := map[string]string{
+		"noRules":       "rules: []",
+		"noName":        "rules:\n  - repos: [a]\n    keepLast: 1",
+		"noRepos":       "rules:\n  - name: a\n    keepLast: 1",
+		"noConditions":  "rules:\n  - name: a\n    repos: [a]",
+		"invalidTime":   "rules:\n  - name: a\n    repos: [a]\n    olderThan: 30 days",
+		"unknownField":  "rules:\n  - name: a\n    repos: [a]\n    keepLast: 1\n    newerThan: 1d",
+		"negativeCount": "rules:\n  - name: a\n    repos: [a]\n    keepLast: -1",
+	}
+	for name, content := range invalidPolicies {
+		_, err = LoadCleanupPolicy(writeCleanupPolicy(t, content))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCleanupRuleEvaluate(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	newer := now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)
+	older := now.Add(-90 * 24 * time.Hour).Format(time.RFC3339)
+	recent := now.Add(-24 * time.Hour).Format(time.RFC3339)
+	artifacts := []serviceutils.ResultItem{
+		{Repo: "libs", Path: "org/lib/3.0", Name: "lib.jar", Created: newer},
+		{Repo: "libs", Path: "org/lib/2.0", Name: "lib.jar", Created: older},
+		{Repo: "libs", Path: "org/lib/2.0", Name: "lib.pom", Created: older, Properties: []serviceutils.Property{{Key: "retain", Value: "true"}}},
+		{Repo: "libs", Path: "org/lib/1.0", Name: "lib.jar", Created: older},
+		{Repo: "libs", Path: "org/lib/0.9", Name: "lib.jar", Created: recent},
+		{Repo: "libs", Path: "org/other/1.0", Name: "other.jar", Created: older},
+	}
+	rule := CleanupRule{Name: "rule", Repos: []string{"libs"}, KeepLast: 1, OlderThan: "30d", NotDownloadedSince: "1w", ExcludeProps: "retain=true"}
+	assert.NoError(t, rule.init())
+	artifacts[3].Stats = []serviceutils.Stat{{Downloaded: recent}}
+	toDelete, err := rule.evaluate(createResultItemsReader(t, artifacts), map[string]bool{"libs/org/lib/1.0/lib.jar": false}, now)
+	assert.NoError(t, err)
+	// 0.9 is the latest created version, 3.0 is too new, the pom is excluded by its property and 1.0 was recently downloaded.
+	if assert.Len(t, toDelete, 1) {
+		assert.Equal(t, "libs/org/lib/2.0/lib.jar", toDelete[0].GetItemRelativePath())
+	}
+
+	// Artifacts referenced by a build are never deleted.
+	artifacts[3].Stats = nil
+	toDelete, err = rule.evaluate(createResultItemsReader(t, artifacts), map[string]bool{"libs/org/lib/2.0/lib.jar": true}, now)
+	assert.NoError(t, err)
+	if assert.Len(t, toDelete, 1) {
+		assert.Equal(t, "libs/org/lib/1.0/lib.jar", toDelete[0].GetItemRelativePath())
+	}
+}
+
+func TestCleanupRuleKeptVersionsTimeZones(t *testing.T) {
+	// 2.0 was created after 1.0, although its local time, in an earlier offset, is earlier.
+	artifacts := []serviceutils.ResultItem{
+		{Repo: "libs", Path: "org/lib/1.0", Name: "lib.jar", Created: "2023-03-26T01:30:00.000+01:00"},
+		{Repo: "libs", Path: "org/lib/2.0", Name: "lib.jar", Created: "2023-03-26T01:00:00.000Z"},
+	}
+	rule := CleanupRule{Name: "rule", Repos: []string{"libs"}, KeepLast: 1}
+	assert.NoError(t, rule.init())
+	keptVersions, err := rule.getKeptVersions(createResultItemsReader(t, artifacts))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"libs/org/lib/2.0": true}, keptVersions)
+}
+
+func TestCleanupRuleKeptVersionsRepositoryRoots(t *testing.T) {
+	// The roots of the repositories aren't ranked against each other, nor against the top-level folders.
+	artifacts := []serviceutils.ResultItem{
+		{Repo: "libs", Path: ".", Name: "a.txt", Created: "2023-03-01T00:00:00.000Z"},
+		{Repo: "other-libs", Path: ".", Name: "b.txt", Created: "2023-03-02T00:00:00.000Z"},
+		{Repo: "libs", Path: "1.0", Name: "lib.jar", Created: "2023-03-03T00:00:00.000Z"},
+		{Repo: "libs", Path: "2.0", Name: "lib.jar", Created: "2023-03-04T00:00:00.000Z"},
+	}
+	rule := CleanupRule{Name: "rule", Repos: []string{"libs", "other-libs"}, KeepLast: 1}
+	assert.NoError(t, rule.init())
+	keptVersions, err := rule.getKeptVersions(createResultItemsReader(t, artifacts))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"libs": true, "other-libs": true, "libs/2.0": true}, keptVersions)
+}
+
+func TestCleanupCommand(t *testing.T) {
+	created := time.Now().Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+	artifacts := []serviceutils.ResultItem{
+		{Repo: "libs-release", Path: "org/lib/3.0", Name: "lib.jar", Type: "file", Size: 3, Created: created},
+		{Repo: "libs-release", Path: "org/lib/2.0", Name: "lib.jar", Type: "file", Size: 2, Created: "2020-01-01T00:00:00.000Z"},
+		{Repo: "libs-release", Path: "org/lib/1.0", Name: "lib.jar", Type: "file", Size: 1, Created: "2019-01-01T00:00:00.000Z"},
+		{Repo: "libs-release", Path: "org/lib/0.1", Name: "lib.jar", Type: "file", Size: 1, Created: "2018-01-01T00:00:00.000Z"},
+	}
+	var mutex sync.Mutex
+	var deleted []string
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/search/aql":
+			query, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			results := artifacts
+			if strings.Contains(string(query), "artifact.module.build.name") {
+				results = artifacts[3:]
+			} else if strings.Contains(string(query), "dependency.module.build.name") {
+				results = artifacts[2:3]
+			}
+			content, err := json.Marshal(map[string]interface{}{"results": results})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	policyPath := writeCleanupPolicy(t, testCleanupPolicy)
+	newCleanupCommand := func() *CleanupCommand {
+		cleanupCommand := NewCleanupCommand().SetPolicyPath(policyPath).SetThreads(1).SetBatchSize(1)
+		cleanupCommand.SetServerDetails(serverDetails)
+		return cleanupCommand
+	}
+
+	// Dry run only prints the report.
+	cleanupCommand := newCleanupCommand()
+	cleanupCommand.SetDryRun(true)
+	assert.NoError(t, cleanupCommand.Run())
+	// The 0.1 and 1.0 versions are referenced by a build, as an artifact and as a dependency.
+	if assert.Len(t, cleanupCommand.Candidates(), 1) {
+		assert.Equal(t, CleanupCandidate{Rule: "old-versions", Artifact: artifacts[1]}, cleanupCommand.Candidates()[0])
+	}
+	assert.Empty(t, deleted)
+
+	cleanupCommand = newCleanupCommand()
+	cleanupCommand.SetQuiet(true)
+	assert.NoError(t, cleanupCommand.Run())
+	assert.Equal(t, 1, cleanupCommand.Result().SuccessCount())
+	assert.Equal(t, 0, cleanupCommand.Result().FailCount())
+	assert.Equal(t, []string{"/libs-release/org/lib/2.0/lib.jar"}, deleted)
+}
+
+func writeCleanupPolicy(t *testing.T, content string) string {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(policyPath, []byte(content), 0600))
+	return policyPath
+}
+
+func createResultItemsReader(t *testing.T, items []serviceutils.ResultItem) *content.ContentReader {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	assert.NoError(t, err)
+	for _, item := range items {
+		writer.Write(item)
+	}
+	assert.NoError(t, writer.Close())
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	t.Cleanup(func() {
+		assert.NoError(t, reader.Close())
+	})
+	return reader
+}
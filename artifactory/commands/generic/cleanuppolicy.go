@@ -0,0 +1,243 @@
+package generic
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"gopkg.in/yaml.v3"
+)
+
+// A cleanup policy is a list of rules, which is read from a YAML file. For example:
+//
+//	rules:
+//	  - name: old-snapshots
+//	    repos: ["libs-snapshot-*"]
+//	    path: "org/acme/*"
+//	    keepLast: 3
+//	    olderThan: 30d
+//	    notDownloadedSince: 3mo
+//	    excludeProps: "retain=true"
+//
+// An artifact is deleted by a rule only if it matches all the rule's conditions.
+// Artifacts which are referenced by a build are never deleted.
+type CleanupPolicy struct {
+	Rules []CleanupRule `yaml:"rules"`
+}
+
+type CleanupRule struct {
+	Name string `yaml:"name"`
+	// Repository names, which may include wildcards.
+	Repos []string `yaml:"repos"`
+	// A path pattern in the repositories, which may include wildcards.
+	Path string `yaml:"path,omitempty"`
+	// The number of the latest versions to keep in each package path.
+	// The versions are the folders the artifacts are in, and the package path is the folder which contains them.
+	// Artifacts at the root of a repository aren't in any version, so they're always kept.
+	KeepLast int `yaml:"keepLast,omitempty"`
+	// The minimal age of the artifacts to delete, such as '30d'.
+	OlderThan string `yaml:"olderThan,omitempty"`
+	// The minimal time since the artifacts to delete were last downloaded, such as '6mo'.
+	// Artifacts which were never downloaded are measured by their age.
+	NotDownloadedSince string `yaml:"notDownloadedSince,omitempty"`
+	// Artifacts with any of these properties, in the 'key1=value1;key2=value2' format, are never deleted.
+	ExcludeProps string `yaml:"excludeProps,omitempty"`
+
+	olderThan          time.Duration
+	notDownloadedSince time.Duration
+	excludeProps       *serviceutils.Properties
+}
+
+// Units of the relative times in the policy, as supported by AQL.
+var policyTimeUnits = map[string]time.Duration{
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+var policyTimeRegexp = regexp.MustCompile(`^(\d+)(h|d|w|mo|y)$`)
+
+func LoadCleanupPolicy(policyPath string) (*CleanupPolicy, error) {
+	content, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	policy := new(CleanupPolicy)
+	if err = decoder.Decode(policy); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the cleanup policy '%s': %s", policyPath, err.Error())
+	}
+	if len(policy.Rules) == 0 {
+		return nil, errorutils.CheckErrorf("the cleanup policy '%s' doesn't include any rules", policyPath)
+	}
+	for i := range policy.Rules {
+		if err = policy.Rules[i].init(); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+// Validates the rule and parses its conditions.
+func (cr *CleanupRule) init() (err error) {
+	if cr.Name == "" {
+		return errorutils.CheckErrorf("all the cleanup rules must have a name")
+	}
+	if len(cr.Repos) == 0 {
+		return errorutils.CheckErrorf("the '%s' cleanup rule must include at least one repository", cr.Name)
+	}
+	if cr.KeepLast < 0 {
+		return errorutils.CheckErrorf("the keepLast value of the '%s' cleanup rule can't be negative", cr.Name)
+	}
+	// A rule without conditions would delete all the artifacts in its repositories.
+	if cr.KeepLast == 0 && cr.OlderThan == "" && cr.NotDownloadedSince == "" {
+		return errorutils.CheckErrorf("the '%s' cleanup rule must include at least one of the keepLast, olderThan and notDownloadedSince conditions", cr.Name)
+	}
+	if cr.olderThan, err = parsePolicyTime(cr.Name, cr.OlderThan); err != nil {
+		return
+	}
+	if cr.notDownloadedSince, err = parsePolicyTime(cr.Name, cr.NotDownloadedSince); err != nil {
+		return
+	}
+	cr.excludeProps, err = serviceutils.ParseProperties(cr.ExcludeProps)
+	return
+}
+
+func parsePolicyTime(ruleName, relativeTime string) (time.Duration, error) {
+	if relativeTime == "" {
+		return 0, nil
+	}
+	match := policyTimeRegexp.FindStringSubmatch(relativeTime)
+	if match == nil {
+		return 0, errorutils.CheckErrorf("the '%s' cleanup rule includes an invalid relative time '%s'. The expected format is a number followed by one of the h, d, w, mo and y units", ruleName, relativeTime)
+	}
+	amount, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	return time.Duration(amount) * policyTimeUnits[match[2]], nil
+}
+
+// Returns the artifacts the rule deletes, out of the artifacts in its repositories and path, which are read from artifactsReader.
+// The artifacts which are referenced by a build are keyed by their paths in referencedArtifacts.
+func (cr *CleanupRule) evaluate(artifactsReader *content.ContentReader, referencedArtifacts map[string]bool, now time.Time) (toDelete []serviceutils.ResultItem, err error) {
+	// The latest versions are kept even if some of their artifacts are excluded, so they're found before filtering the artifacts.
+	keptVersions, err := cr.getKeptVersions(artifactsReader)
+	if err != nil {
+		return
+	}
+	for artifact := new(serviceutils.ResultItem); artifactsReader.NextRecord(artifact) == nil; artifact = new(serviceutils.ResultItem) {
+		if keptVersions[path.Join(artifact.Repo, artifact.Path)] || referencedArtifacts[artifact.GetItemRelativePath()] || cr.isExcludedByProps(*artifact) {
+			continue
+		}
+		var matched bool
+		if matched, err = cr.matchesTimeConditions(*artifact, now); err != nil {
+			return
+		}
+		if matched {
+			toDelete = append(toDelete, *artifact)
+		}
+	}
+	artifactsReader.Reset()
+	err = artifactsReader.GetError()
+	return
+}
+
+// Returns the paths of the latest versions in each package path, by the time their newest artifact was created.
+// The provided reader is reset.
+func (cr *CleanupRule) getKeptVersions(artifactsReader *content.ContentReader) (map[string]bool, error) {
+	keptVersions := make(map[string]bool)
+	if cr.KeepLast == 0 {
+		return keptVersions, nil
+	}
+	versionsCreated := make(map[string]time.Time)
+	for artifact := new(serviceutils.ResultItem); artifactsReader.NextRecord(artifact) == nil; artifact = new(serviceutils.ResultItem) {
+		version := path.Join(artifact.Repo, artifact.Path)
+		if version == artifact.Repo {
+			// The roots of different repositories mustn't be ranked against each other as versions of the same package.
+			keptVersions[version] = true
+			continue
+		}
+		created, err := parseArtifactTime(artifact.Created)
+		if err != nil {
+			return nil, err
+		}
+		if created.After(versionsCreated[version]) {
+			versionsCreated[version] = created
+		}
+	}
+	artifactsReader.Reset()
+	if err := artifactsReader.GetError(); err != nil {
+		return nil, err
+	}
+	packageVersions := make(map[string][]string)
+	for version := range versionsCreated {
+		packagePath := path.Dir(version)
+		packageVersions[packagePath] = append(packageVersions[packagePath], version)
+	}
+	for _, versions := range packageVersions {
+		sort.Slice(versions, func(i, j int) bool {
+			return versionsCreated[versions[i]].After(versionsCreated[versions[j]])
+		})
+		for i := 0; i < len(versions) && i < cr.KeepLast; i++ {
+			keptVersions[versions[i]] = true
+		}
+	}
+	return keptVersions, nil
+}
+
+func (cr *CleanupRule) isExcludedByProps(artifact serviceutils.ResultItem) bool {
+	excludedProps := cr.excludeProps.ToMap()
+	for _, prop := range artifact.Properties {
+		for _, value := range excludedProps[prop.Key] {
+			if value == prop.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cr *CleanupRule) matchesTimeConditions(artifact serviceutils.ResultItem, now time.Time) (bool, error) {
+	created, err := parseArtifactTime(artifact.Created)
+	if err != nil {
+		return false, err
+	}
+	if cr.olderThan > 0 && created.After(now.Add(-cr.olderThan)) {
+		return false, nil
+	}
+	if cr.notDownloadedSince > 0 {
+		lastUsed := created
+		if downloaded := getLastDownloaded(artifact); downloaded != "" {
+			if lastUsed, err = parseArtifactTime(downloaded); err != nil {
+				return false, err
+			}
+		}
+		if lastUsed.After(now.Add(-cr.notDownloadedSince)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func getLastDownloaded(artifact serviceutils.ResultItem) string {
+	if len(artifact.Stats) == 0 {
+		return ""
+	}
+	return artifact.Stats[0].Downloaded
+}
+
+func parseArtifactTime(artifactTime string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, artifactTime)
+	return parsed, errorutils.CheckError(err)
+}
@@ -26,6 +26,10 @@ type DownloadCommand struct {
 	GenericCommand
 	configuration *utils.DownloadConfiguration
 	progress      ioUtils.ProgressMgr
+	// When true, files completed by previous runs of the same download are skipped, according to the resume manifest.
+	resume bool
+	// When true, local files are verified against the checksums in Artifactory, and only mismatches are downloaded.
+	verify bool
 }
 
 func NewDownloadCommand() *DownloadCommand {
@@ -46,6 +50,28 @@ func (dc *DownloadCommand) SetConfiguration(configuration *utils.DownloadConfigu
 	return dc
 }
 
+func (dc *DownloadCommand) Resume() bool {
+	return dc.resume
+}
+
+func (dc *DownloadCommand) SetResume(resume bool) *DownloadCommand {
+	dc.resume = resume
+	return dc
+}
+
+func (dc *DownloadCommand) Verify() bool {
+	return dc.verify
+}
+
+func (dc *DownloadCommand) SetVerify(verify bool) *DownloadCommand {
+	dc.verify = verify
+	return dc
+}
+
+func (dc *DownloadCommand) isResumable() bool {
+	return (dc.resume || dc.verify) && !dc.DryRun()
+}
+
 func (dc *DownloadCommand) SetProgress(progress ioUtils.ProgressMgr) {
 	dc.progress = progress
 }
@@ -107,10 +133,15 @@ func (dc *DownloadCommand) download() error {
 	// Perform download.
 	// In case of build-info collection/sync-deletes operation/a detailed summary is required, we use the download service which provides results file reader,
 	// otherwise we use the download service which provides only general counters.
+	// A resumable download always provides results file readers.
 	var totalDownloaded, totalFailed int
 	var summary *serviceutils.OperationSummary
-	if toCollect || dc.SyncDeletesPath() != "" || dc.DetailedSummary() {
-		summary, err = servicesManager.DownloadFilesWithSummary(downloadParamsArray...)
+	if dc.isResumable() || toCollect || dc.SyncDeletesPath() != "" || dc.DetailedSummary() {
+		if dc.isResumable() {
+			summary, err = dc.resumableDownload(servicesManager, downloadParamsArray)
+		} else {
+			summary, err = servicesManager.DownloadFilesWithSummary(downloadParamsArray...)
+		}
 		if err != nil {
 			errorOccurred = true
 			log.Error(err)
@@ -0,0 +1,410 @@
+package generic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The resume manifests are kept in this directory, under the CLI temp dir.
+	downloadManifestsDirName = "jfrog-download-manifests"
+	// The maximum number of files downloaded in each batch of a resumable download.
+	// The resume manifest is saved after each batch, so an interrupted download loses at most one batch.
+	resumableDownloadBatchSize = 100
+)
+
+// The resume manifest records the files which were downloaded completely, so that an interrupted download continues where it stopped.
+type downloadManifest struct {
+	// The completed files, by their local paths.
+	Files map[string]*downloadManifestEntry `json:"files"`
+	path  string
+}
+
+type downloadManifestEntry struct {
+	ArtifactoryPath string    `json:"artifactoryPath"`
+	Sha256          string    `json:"sha256,omitempty"`
+	Sha1            string    `json:"sha1,omitempty"`
+	Size            int64     `json:"size"`
+	ModTime         time.Time `json:"modTime"`
+}
+
+// Returns the path of the resume manifest of the download.
+// A separate manifest is kept for each combination of Artifactory server, spec and working directory.
+func getDownloadManifestPath(artifactoryUrl string, downloadSpec *spec.SpecFiles) (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	specContent, err := json.Marshal(downloadSpec)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	checksum := sha256.Sum256([]byte(strings.Join([]string{artifactoryUrl, workingDir, string(specContent)}, "\n")))
+	return filepath.Join(coreutils.GetCliPersistentTempDirPath(), downloadManifestsDirName, hex.EncodeToString(checksum[:])+".json"), nil
+}
+
+func loadDownloadManifest(manifestPath string) (*downloadManifest, error) {
+	manifest := &downloadManifest{Files: make(map[string]*downloadManifestEntry), path: manifestPath}
+	exists, err := fileutils.IsFileExists(manifestPath, false)
+	if err != nil || !exists {
+		return manifest, err
+	}
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(content, manifest); err != nil || manifest.Files == nil {
+		log.Warn(fmt.Sprintf("The download resume manifest at '%s' is corrupted and will be ignored.", manifestPath))
+		manifest.Files = make(map[string]*downloadManifestEntry)
+	}
+	return manifest, nil
+}
+
+// Saves the manifest by replacing the previous file, so that an interruption never leaves a partially written manifest.
+func (m *downloadManifest) save() error {
+	if err := fileutils.CreateDirIfNotExist(filepath.Dir(m.path)); err != nil {
+		return err
+	}
+	content, err := json.Marshal(m)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempPath := m.path + ".tmp"
+	if err = os.WriteFile(tempPath, content, 0600); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.Rename(tempPath, m.path))
+}
+
+func (m *downloadManifest) remove() error {
+	if err := os.Remove(m.path); err != nil && !os.IsNotExist(err) {
+		return errorutils.CheckError(err)
+	}
+	return nil
+}
+
+// Returns true if the local file was recorded as completed, and neither the file nor the artifact have changed since.
+// The local file's size and modification time are compared, to avoid calculating its checksum.
+func (m *downloadManifest) isCompleted(localPath string, item *serviceutils.ResultItem) bool {
+	entry := m.Files[localPath]
+	if entry == nil || entry.ArtifactoryPath != item.GetItemRelativePath() || entry.Sha256 != item.Sha256 || entry.Sha1 != item.Actual_Sha1 {
+		return false
+	}
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return false
+	}
+	return info.Size() == entry.Size && info.ModTime().Equal(entry.ModTime)
+}
+
+func (m *downloadManifest) addCompleted(localPath string, item *serviceutils.ResultItem) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	m.Files[localPath] = &downloadManifestEntry{
+		ArtifactoryPath: item.GetItemRelativePath(),
+		Sha256:          item.Sha256,
+		Sha1:            item.Actual_Sha1,
+		Size:            info.Size(),
+		ModTime:         info.ModTime(),
+	}
+	return nil
+}
+
+// An artifact found by the download spec, and the local path it's downloaded to.
+type plannedDownload struct {
+	item      serviceutils.ResultItem
+	localPath string
+	// The parameters of the spec's file group which found the artifact.
+	params *services.DownloadParams
+}
+
+// A download which skips the files completed by previous runs, according to the resume manifest.
+// In verify mode, the local files are checked against the checksums in Artifactory instead, and only mismatches are downloaded.
+type resumableDownload struct {
+	servicesManager        artifactory.ArtifactoryServicesManager
+	rtUrl                  string
+	manifest               *downloadManifest
+	verify                 bool
+	transferDetailsWriter  *content.ContentWriter
+	artifactsDetailsWriter *content.ContentWriter
+	totalSucceeded         int
+	totalFailed            int
+	mismatches             int
+}
+
+// Downloads the files of the spec which weren't downloaded yet.
+// The returned summary includes the skipped files, as if they were downloaded, like files which already exist locally.
+func (dc *DownloadCommand) resumableDownload(servicesManager artifactory.ArtifactoryServicesManager, downloadParamsArray []services.DownloadParams) (*serviceutils.OperationSummary, error) {
+	manifestPath, err := getDownloadManifestPath(dc.serverDetails.ArtifactoryUrl, dc.Spec())
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadDownloadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Using the download resume manifest at", manifestPath)
+	rd := &resumableDownload{servicesManager: servicesManager, rtUrl: dc.serverDetails.ArtifactoryUrl, manifest: manifest, verify: dc.verify}
+	if rd.transferDetailsWriter, err = content.NewContentWriter(content.DefaultKey, true, false); err != nil {
+		return nil, err
+	}
+	if rd.artifactsDetailsWriter, err = content.NewContentWriter(content.DefaultKey, true, false); err != nil {
+		return nil, err
+	}
+	err = rd.run(downloadParamsArray)
+	summary, summaryErr := rd.createSummary()
+	if err == nil {
+		err = summaryErr
+	}
+	if err == nil && rd.totalFailed == 0 {
+		// The download is complete, so there's nothing to resume.
+		err = manifest.remove()
+	}
+	return summary, err
+}
+
+func (rd *resumableDownload) run(downloadParamsArray []services.DownloadParams) error {
+	var pending []*plannedDownload
+	var unplannedParams []services.DownloadParams
+	plannedPaths := make(map[string]bool)
+	for i := range downloadParamsArray {
+		if !isPlannableDownload(&downloadParamsArray[i]) {
+			unplannedParams = append(unplannedParams, downloadParamsArray[i])
+			continue
+		}
+		groupPending, err := rd.plan(&downloadParamsArray[i], plannedPaths)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, groupPending...)
+	}
+	if rd.verify {
+		log.Info(fmt.Sprintf("Verification found %d local files which don't match the checksums in Artifactory.", rd.mismatches))
+	}
+	if err := rd.manifest.save(); err != nil {
+		return err
+	}
+	// A failure doesn't stop the following batches, and the first error is returned at the end.
+	var downloadErr error
+	// File groups which can't be planned, such as release bundles, are downloaded as usual.
+	if len(unplannedParams) > 0 {
+		downloadErr = rd.download(unplannedParams, nil)
+	}
+	for start := 0; start < len(pending); start += resumableDownloadBatchSize {
+		end := start + resumableDownloadBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+		if err := rd.download(createBatchDownloadParams(batch), batch); err != nil && downloadErr == nil {
+			downloadErr = err
+		}
+	}
+	return downloadErr
+}
+
+// File groups which download more than plain files, such as archives to extract or symlinks, are downloaded without planning.
+func isPlannableDownload(params *services.DownloadParams) bool {
+	return params.Bundle == "" && !params.Explode && !params.Symlink && !params.IncludeDirs
+}
+
+// Searches the artifacts of the file group, and returns the ones which should be downloaded.
+// The other artifacts were already downloaded, and are added to the summary.
+func (rd *resumableDownload) plan(params *services.DownloadParams, plannedPaths map[string]bool) (pending []*plannedDownload, err error) {
+	// The search sets the query it runs in the params, so the file group's params are copied.
+	commonParams := *params.CommonParams
+	reader, err := rd.servicesManager.SearchFiles(services.SearchParams{CommonParams: &commonParams})
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	for item := new(serviceutils.ResultItem); reader.NextRecord(item) == nil; item = new(serviceutils.ResultItem) {
+		if item.Type == "folder" {
+			continue
+		}
+		// Calculate the local path the same way the download service does.
+		target, placeholdersUsed, e := clientutils.BuildTargetPath(params.GetPattern(), item.GetItemRelativePath(), params.GetTarget(), true)
+		if e != nil {
+			return nil, e
+		}
+		localDir, localName := fileutils.GetLocalPathAndFile(item.Name, item.Path, target, params.IsFlat(), placeholdersUsed)
+		planned := &plannedDownload{item: *item, localPath: filepath.Join(localDir, localName), params: params}
+		// Like the download service, the first artifact downloaded to a local path wins.
+		if plannedPaths[planned.localPath] {
+			continue
+		}
+		plannedPaths[planned.localPath] = true
+		completed, e := rd.isCompleted(planned)
+		if e != nil {
+			return nil, e
+		}
+		if completed {
+			rd.addSkipped(planned)
+			continue
+		}
+		pending = append(pending, planned)
+	}
+	err = reader.GetError()
+	return
+}
+
+func (rd *resumableDownload) isCompleted(planned *plannedDownload) (bool, error) {
+	if !rd.verify {
+		return rd.manifest.isCompleted(planned.localPath, &planned.item), nil
+	}
+	exists, err := fileutils.IsFileExists(planned.localPath, false)
+	if err != nil || !exists {
+		return false, err
+	}
+	details, err := fileutils.GetFileDetails(planned.localPath, true)
+	if err != nil {
+		return false, err
+	}
+	// Artifactory may not have calculated the SHA256 of older artifacts.
+	matches := details.Checksum.Sha1 == planned.item.Actual_Sha1
+	if planned.item.Sha256 != "" {
+		matches = details.Checksum.Sha256 == planned.item.Sha256
+	}
+	if !matches {
+		rd.mismatches++
+		log.Warn(fmt.Sprintf("'%s' doesn't match the checksum of '%s' in Artifactory, and will be downloaded again.", planned.localPath, planned.item.GetItemRelativePath()))
+		return false, nil
+	}
+	return true, rd.manifest.addCompleted(planned.localPath, &planned.item)
+}
+
+func (rd *resumableDownload) addSkipped(planned *plannedDownload) {
+	log.Info("Skipping", planned.item.GetItemRelativePath(), "- already downloaded to", planned.localPath)
+	rd.transferDetailsWriter.Write(clientutils.FileTransferDetails{SourcePath: planned.item.GetItemRelativePath(), RtUrl: rd.rtUrl, TargetPath: planned.localPath})
+	rd.artifactsDetailsWriter.Write(createArtifactDetails(&planned.item))
+	rd.totalSucceeded++
+}
+
+func createArtifactDetails(item *serviceutils.ResultItem) serviceutils.ArtifactDetails {
+	return serviceutils.ArtifactDetails{
+		ArtifactoryPath: item.GetItemRelativePath(),
+		Checksums:       buildinfo.Checksum{Sha1: item.Actual_Sha1, Md5: item.Actual_Md5, Sha256: item.Sha256},
+	}
+}
+
+// Creates the parameters for downloading the artifacts of the batch to their planned local paths.
+// The artifacts are found by an AQL query, grouped by their local directory.
+func createBatchDownloadParams(batch []*plannedDownload) []services.DownloadParams {
+	var paramsArray []services.DownloadParams
+	var conditions [][]map[string]string
+	paramsIndexes := make(map[string]int)
+	for _, planned := range batch {
+		localDir, localName := filepath.Split(planned.localPath)
+		target := filepath.ToSlash(localDir)
+		if localName != planned.item.Name {
+			// The artifact is renamed, so the target must include the local file name.
+			target = filepath.ToSlash(planned.localPath)
+		}
+		// The file group is part of the key, to keep the group's download configuration.
+		key := fmt.Sprintf("%p:%s", planned.params, target)
+		index, exists := paramsIndexes[key]
+		if !exists {
+			params := services.NewDownloadParams()
+			params.Target = target
+			params.Flat = true
+			params.MinSplitSize = planned.params.MinSplitSize
+			params.SplitCount = planned.params.SplitCount
+			params.SkipChecksum = planned.params.SkipChecksum
+			index = len(paramsArray)
+			paramsIndexes[key] = index
+			paramsArray = append(paramsArray, params)
+			conditions = append(conditions, nil)
+		}
+		conditions[index] = append(conditions[index], map[string]string{"repo": planned.item.Repo, "path": planned.item.Path, "name": planned.item.Name})
+	}
+	for i := range paramsArray {
+		// Marshaling a map of strings can't fail.
+		query, _ := json.Marshal(map[string]interface{}{"$or": conditions[i]})
+		paramsArray[i].Aql = serviceutils.Aql{ItemsFind: string(query)}
+	}
+	return paramsArray
+}
+
+// Downloads the files, adds the results to the summary and records the completed files of the batch in the manifest.
+func (rd *resumableDownload) download(paramsArray []services.DownloadParams, batch []*plannedDownload) (err error) {
+	summary, err := rd.servicesManager.DownloadFilesWithSummary(paramsArray...)
+	if summary == nil {
+		return
+	}
+	defer func() {
+		e := summary.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	rd.totalSucceeded += summary.TotalSucceeded
+	rd.totalFailed += summary.TotalFailed
+	plannedByPath := make(map[string]*plannedDownload, len(batch))
+	for _, planned := range batch {
+		plannedByPath[planned.localPath] = planned
+	}
+	for transferDetails := new(clientutils.FileTransferDetails); summary.TransferDetailsReader.NextRecord(transferDetails) == nil; transferDetails = new(clientutils.FileTransferDetails) {
+		rd.transferDetailsWriter.Write(*transferDetails)
+		if planned, exists := plannedByPath[filepath.Clean(transferDetails.TargetPath)]; exists {
+			if e := rd.manifest.addCompleted(planned.localPath, &planned.item); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	for artifactDetails := new(serviceutils.ArtifactDetails); summary.ArtifactsDetailsReader.NextRecord(artifactDetails) == nil; artifactDetails = new(serviceutils.ArtifactDetails) {
+		rd.artifactsDetailsWriter.Write(*artifactDetails)
+	}
+	if e := rd.manifest.save(); e != nil && err == nil {
+		err = e
+	}
+	return
+}
+
+func (rd *resumableDownload) createSummary() (*serviceutils.OperationSummary, error) {
+	summary := &serviceutils.OperationSummary{TotalSucceeded: rd.totalSucceeded, TotalFailed: rd.totalFailed}
+	var err error
+	summary.TransferDetailsReader, err = closeAndRead(rd.transferDetailsWriter)
+	if err != nil {
+		return nil, err
+	}
+	summary.ArtifactsDetailsReader, err = closeAndRead(rd.artifactsDetailsWriter)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func closeAndRead(writer *content.ContentWriter) (*content.ContentReader, error) {
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if writer.IsEmpty() {
+		return content.NewEmptyContentReader(content.DefaultKey), nil
+	}
+	return content.NewContentReader(writer.GetFilePath(), content.DefaultKey), nil
+}
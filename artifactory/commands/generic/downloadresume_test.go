@@ -0,0 +1,121 @@
+package generic
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumableDownload(t *testing.T) {
+	remoteFiles := map[string]string{"a.txt": "content of a", "b.txt": "content of b"}
+	var mutex sync.Mutex
+	var downloaded []string
+	failedFile := "b.txt"
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/system/version":
+			_, err := w.Write([]byte(`{"version":"7.50.0"}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/api/search/aql":
+			query, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var results []serviceutils.ResultItem
+			for name, fileContent := range remoteFiles {
+				// The batches of the resumable download query specific files.
+				if strings.Contains(string(query), `"name":"`) && !strings.Contains(string(query), `"name":"`+name) {
+					continue
+				}
+				results = append(results, createTestResultItem(name, fileContent))
+			}
+			content, err := json.Marshal(map[string]interface{}{"results": results})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repo/dir/"):
+			name := strings.TrimPrefix(r.URL.Path, "/repo/dir/")
+			if name == failedFile {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			downloaded = append(downloaded, name)
+			_, err := w.Write([]byte(remoteFiles[name]))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	localDir := t.TempDir()
+	downloadSpec := spec.NewBuilder().Pattern("repo/dir/*").Target(localDir + "/").Flat(true).BuildSpec()
+	newDownloadCommand := func() *DownloadCommand {
+		downloadCommand := NewDownloadCommand().SetConfiguration(&utils.DownloadConfiguration{Threads: 1}).SetBuildConfiguration(new(utils.BuildConfiguration))
+		downloadCommand.SetServerDetails(serverDetails).SetSpec(downloadSpec)
+		return downloadCommand
+	}
+	manifestPath, err := getDownloadManifestPath(serverDetails.ArtifactoryUrl, downloadSpec)
+	assert.NoError(t, err)
+
+	// The first run is interrupted by a failure, and the completed file is recorded in the manifest.
+	downloadCommand := newDownloadCommand().SetResume(true)
+	assert.Error(t, downloadCommand.Run())
+	assert.Equal(t, 1, downloadCommand.Result().SuccessCount())
+	assert.Equal(t, 1, downloadCommand.Result().FailCount())
+	assert.Equal(t, []string{"a.txt"}, downloaded)
+	manifest, err := loadDownloadManifest(manifestPath)
+	assert.NoError(t, err)
+	assert.Contains(t, manifest.Files, filepath.Join(localDir, "a.txt"))
+
+	// The resumed run downloads only the remaining file, and removes the manifest once the download is complete.
+	failedFile = ""
+	downloaded = nil
+	downloadCommand = newDownloadCommand().SetResume(true)
+	assert.NoError(t, downloadCommand.Run())
+	assert.Equal(t, 2, downloadCommand.Result().SuccessCount())
+	assert.Equal(t, []string{"b.txt"}, downloaded)
+	assert.NoFileExists(t, manifestPath)
+
+	// The verification pass re-downloads only the local files which don't match the checksums in Artifactory.
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "a.txt"), []byte("corrupted"), 0600))
+	downloaded = nil
+	downloadCommand = newDownloadCommand().SetVerify(true)
+	assert.NoError(t, downloadCommand.Run())
+	assert.Equal(t, 2, downloadCommand.Result().SuccessCount())
+	assert.Equal(t, []string{"a.txt"}, downloaded)
+	localContent, err := os.ReadFile(filepath.Join(localDir, "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, remoteFiles["a.txt"], string(localContent))
+}
+
+func createTestResultItem(name, fileContent string) serviceutils.ResultItem {
+	sha1Sum := sha1.Sum([]byte(fileContent))
+	sha256Sum := sha256.Sum256([]byte(fileContent))
+	md5Sum := md5.Sum([]byte(fileContent))
+	return serviceutils.ResultItem{
+		Repo:        "repo",
+		Path:        "dir",
+		Name:        name,
+		Type:        "file",
+		Size:        int64(len(fileContent)),
+		Actual_Sha1: hex.EncodeToString(sha1Sum[:]),
+		Actual_Md5:  hex.EncodeToString(md5Sum[:]),
+		Sha256:      hex.EncodeToString(sha256Sum[:]),
+	}
+}
@@ -0,0 +1,326 @@
+package generic
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type SyncActionType string
+
+const (
+	SyncAdd    SyncActionType = "add"
+	SyncUpdate SyncActionType = "update"
+	SyncDelete SyncActionType = "delete"
+)
+
+type SyncAction struct {
+	Type SyncActionType
+	// The path of the file, relative to the synchronized local directory and Artifactory path.
+	RelativePath string
+	// Empty for deleted artifacts.
+	LocalPath string
+	// The path of the artifact, including its repository.
+	ArtifactoryPath string
+	artifact        *serviceutils.ResultItem
+}
+
+// The actions which make the Artifactory path identical to the local directory.
+type SyncPlan struct {
+	Actions []SyncAction
+	// The number of files which are identical in both sides.
+	Unchanged int
+}
+
+func (sp *SyncPlan) count(actionType SyncActionType) (count int) {
+	for _, action := range sp.Actions {
+		if action.Type == actionType {
+			count++
+		}
+	}
+	return
+}
+
+type syncPlanRow struct {
+	Action string `col-name:"Action"`
+	Path   string `col-name:"Path"`
+}
+
+// SyncCommand synchronizes a path in Artifactory with a local directory, in one direction.
+// It first computes a plan, by comparing the SHA256 checksums of the local files and the artifacts.
+// It then uploads the added and updated files, and deletes the artifacts which don't exist locally.
+// In dry-run mode, only the plan is printed.
+type SyncCommand struct {
+	GenericCommand
+	localPath  string
+	targetPath string
+	threads    int
+	plan       *SyncPlan
+}
+
+func NewSyncCommand() *SyncCommand {
+	return &SyncCommand{GenericCommand: *NewGenericCommand()}
+}
+
+func (sc *SyncCommand) SetLocalPath(localPath string) *SyncCommand {
+	sc.localPath = localPath
+	return sc
+}
+
+// The target path in Artifactory, in the 'repo/path/' format.
+func (sc *SyncCommand) SetTargetPath(targetPath string) *SyncCommand {
+	sc.targetPath = targetPath
+	return sc
+}
+
+func (sc *SyncCommand) Threads() int {
+	return sc.threads
+}
+
+func (sc *SyncCommand) SetThreads(threads int) *SyncCommand {
+	sc.threads = threads
+	return sc
+}
+
+// Returns the plan computed by the last run.
+func (sc *SyncCommand) Plan() *SyncPlan {
+	return sc.plan
+}
+
+func (sc *SyncCommand) CommandName() string {
+	return "rt_sync"
+}
+
+func (sc *SyncCommand) Run() (err error) {
+	targetPath, err := sc.getTargetPath()
+	if err != nil {
+		return
+	}
+	servicesManager, err := utils.CreateServiceManagerWithThreads(sc.serverDetails, false, sc.threads, sc.retries, sc.retryWaitTimeMilliSecs)
+	if err != nil {
+		return
+	}
+	log.Info("Calculating the checksums of the local files...")
+	localChecksums, err := getLocalFilesChecksums(sc.localPath)
+	if err != nil {
+		return
+	}
+	log.Info("Searching the artifacts in", targetPath+"...")
+	artifacts, err := searchSyncArtifacts(servicesManager, targetPath)
+	if err != nil {
+		return
+	}
+	sc.plan = createSyncPlan(sc.localPath, targetPath, localChecksums, artifacts)
+	if err = printSyncPlan(sc.plan); err != nil || sc.DryRun() || len(sc.plan.Actions) == 0 {
+		return
+	}
+	if sc.plan.count(SyncDelete) > 0 && !sc.Quiet() && !coreutils.AskYesNo("The above plan deletes artifacts from Artifactory. Are you sure you want to continue?", false) {
+		return
+	}
+	return sc.executePlan(servicesManager)
+}
+
+// Returns the target path, with a trailing slash.
+func (sc *SyncCommand) getTargetPath() (string, error) {
+	targetPath := strings.TrimPrefix(sc.targetPath, "/")
+	if targetPath == "" || strings.ContainsAny(targetPath, "*?{}") {
+		return "", errorutils.CheckErrorf("the sync target must be an Artifactory path in the 'repo/path/' format, without wildcards, but '%s' was found", sc.targetPath)
+	}
+	if !strings.HasSuffix(targetPath, "/") {
+		targetPath += "/"
+	}
+	return targetPath, nil
+}
+
+// Returns the checksums of the files in the local directory, by their slash-separated relative paths.
+// Symlinks and other special files are not synchronized.
+func getLocalFilesChecksums(localPath string) (map[string]buildinfo.Checksum, error) {
+	checksums := make(map[string]buildinfo.Checksum)
+	err := filepath.WalkDir(localPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			if !entry.IsDir() {
+				log.Debug("Skipping", filePath, "since it's not a regular file.")
+			}
+			return nil
+		}
+		relativePath, err := filepath.Rel(localPath, filePath)
+		if err != nil {
+			return err
+		}
+		details, err := fileutils.GetFileDetails(filePath, true)
+		if err != nil {
+			return err
+		}
+		checksums[filepath.ToSlash(relativePath)] = details.Checksum
+		return nil
+	})
+	return checksums, errorutils.CheckError(err)
+}
+
+// Returns the artifacts under the target path, by their paths relative to the target path.
+func searchSyncArtifacts(servicesManager artifactory.ArtifactoryServicesManager, targetPath string) (artifacts map[string]*serviceutils.ResultItem, err error) {
+	searchParams := services.NewSearchParams()
+	searchParams.Pattern = targetPath + "*"
+	searchParams.Recursive = true
+	reader, err := servicesManager.SearchFiles(searchParams)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	_, pathInRepo, _ := strings.Cut(targetPath, "/")
+	artifacts = make(map[string]*serviceutils.ResultItem)
+	for item := new(serviceutils.ResultItem); reader.NextRecord(item) == nil; item = new(serviceutils.ResultItem) {
+		relativePath := strings.TrimPrefix(path.Join(item.Path, item.Name), pathInRepo)
+		artifacts[relativePath] = item
+	}
+	err = reader.GetError()
+	return
+}
+
+func createSyncPlan(localPath, targetPath string, localChecksums map[string]buildinfo.Checksum, artifacts map[string]*serviceutils.ResultItem) *SyncPlan {
+	plan := new(SyncPlan)
+	for relativePath, checksum := range localChecksums {
+		action := SyncAction{RelativePath: relativePath, LocalPath: filepath.Join(localPath, filepath.FromSlash(relativePath)), ArtifactoryPath: targetPath + relativePath}
+		artifact, exists := artifacts[relativePath]
+		switch {
+		case !exists:
+			action.Type = SyncAdd
+		case !isSameChecksum(checksum, artifact):
+			action.Type = SyncUpdate
+		default:
+			plan.Unchanged++
+			continue
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+	for relativePath, artifact := range artifacts {
+		if _, exists := localChecksums[relativePath]; !exists {
+			plan.Actions = append(plan.Actions, SyncAction{Type: SyncDelete, RelativePath: relativePath, ArtifactoryPath: artifact.GetItemRelativePath(), artifact: artifact})
+		}
+	}
+	sort.Slice(plan.Actions, func(i, j int) bool {
+		return plan.Actions[i].RelativePath < plan.Actions[j].RelativePath
+	})
+	return plan
+}
+
+// Artifactory may not have calculated the SHA256 of older artifacts, so their SHA1 is compared instead.
+func isSameChecksum(localChecksum buildinfo.Checksum, artifact *serviceutils.ResultItem) bool {
+	if artifact.Sha256 == "" {
+		return localChecksum.Sha1 == artifact.Actual_Sha1
+	}
+	return localChecksum.Sha256 == artifact.Sha256
+}
+
+func printSyncPlan(plan *SyncPlan) error {
+	var rows []syncPlanRow
+	for _, action := range plan.Actions {
+		rows = append(rows, syncPlanRow{Action: string(action.Type), Path: action.RelativePath})
+	}
+	if err := coreutils.PrintTable(rows, "Sync Plan", "Everything is up to date", false); err != nil {
+		return err
+	}
+	log.Info("Plan:", plan.count(SyncAdd), "to add,", plan.count(SyncUpdate), "to update,", plan.count(SyncDelete), "to delete,", plan.Unchanged, "unchanged.")
+	return nil
+}
+
+func (sc *SyncCommand) executePlan(servicesManager artifactory.ArtifactoryServicesManager) error {
+	uploaded, failedUploads, uploadErr := sc.uploadFiles()
+	deleted, failedDeletes, deleteErr := sc.deleteArtifacts(servicesManager)
+	sc.result.SetSuccessCount(uploaded + deleted)
+	sc.result.SetFailCount(failedUploads + failedDeletes)
+	if uploadErr != nil {
+		return uploadErr
+	}
+	return deleteErr
+}
+
+// Uploads the added and updated files, each to its exact path in Artifactory.
+func (sc *SyncCommand) uploadFiles() (successCount, failCount int, err error) {
+	var uploadParamsArray []services.UploadParams
+	minChecksumDeploySize, err := getMinChecksumDeploySize()
+	if err != nil {
+		return
+	}
+	for _, action := range sc.plan.Actions {
+		if action.Type == SyncDelete {
+			continue
+		}
+		uploadParams := services.NewUploadParams()
+		uploadParams.Target = action.ArtifactoryPath
+		uploadParams.Flat = true
+		if err = setExactUploadPattern(&uploadParams, action.LocalPath); err != nil {
+			return
+		}
+		uploadParams.MinChecksumDeploy = minChecksumDeploySize
+		uploadParamsArray = append(uploadParamsArray, uploadParams)
+	}
+	if len(uploadParamsArray) == 0 {
+		return
+	}
+	servicesManager, err := utils.CreateUploadServiceManager(sc.serverDetails, sc.threads, sc.retries, sc.retryWaitTimeMilliSecs, false, nil)
+	if err != nil {
+		return
+	}
+	successCount, failCount, err = servicesManager.UploadFiles(uploadParamsArray...)
+	if err != nil {
+		return
+	}
+	// Files which were removed or renamed after the plan was computed aren't uploaded.
+	if missingCount := len(uploadParamsArray) - successCount - failCount; missingCount > 0 {
+		failCount += missingCount
+		err = errorutils.CheckErrorf("%d of the %d planned files were not found in the local directory and weren't uploaded", missingCount, len(uploadParamsArray))
+	}
+	return
+}
+
+// Deletes the artifacts which don't exist in the local directory.
+func (sc *SyncCommand) deleteArtifacts(servicesManager artifactory.ArtifactoryServicesManager) (successCount, failCount int, err error) {
+	toDelete := sc.plan.count(SyncDelete)
+	if toDelete == 0 {
+		return
+	}
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return
+	}
+	for _, action := range sc.plan.Actions {
+		if action.Type == SyncDelete {
+			writer.Write(*action.artifact)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	successCount, err = servicesManager.DeleteFiles(reader)
+	failCount = toDelete - successCount
+	return
+}
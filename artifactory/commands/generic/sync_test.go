@@ -0,0 +1,133 @@
+package generic
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncCommand(t *testing.T) {
+	localDir := t.TempDir()
+	localFiles := map[string]string{"a.txt": "unchanged", "b.txt": "updated", "d/e.txt": "added"}
+	for relativePath, fileContent := range localFiles {
+		localPath := filepath.Join(localDir, filepath.FromSlash(relativePath))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0700))
+		assert.NoError(t, os.WriteFile(localPath, []byte(fileContent), 0600))
+	}
+	remoteItems := []serviceutils.ResultItem{
+		createTestResultItem("a.txt", "unchanged"),
+		createTestResultItem("b.txt", "outdated"),
+		createTestResultItem("c.txt", "deleted"),
+	}
+	// Artifacts without a SHA256 are compared by their SHA1.
+	remoteItems[0].Sha256 = ""
+
+	var mutex sync.Mutex
+	var uploaded, deleted []string
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/system/version":
+			_, err := w.Write([]byte(`{"version":"7.50.0"}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/api/search/aql":
+			content, err := json.Marshal(map[string]interface{}{"results": remoteItems})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut:
+			uploaded = append(uploaded, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	newSyncCommand := func() *SyncCommand {
+		syncCommand := NewSyncCommand().SetLocalPath(localDir).SetTargetPath("repo/dir").SetThreads(1)
+		syncCommand.SetServerDetails(serverDetails)
+		return syncCommand
+	}
+
+	// Dry run only computes the plan.
+	syncCommand := newSyncCommand()
+	syncCommand.SetDryRun(true)
+	assert.NoError(t, syncCommand.Run())
+	plan := syncCommand.Plan()
+	assert.Equal(t, 1, plan.Unchanged)
+	if assert.Len(t, plan.Actions, 3) {
+		assert.Equal(t, SyncAction{Type: SyncUpdate, RelativePath: "b.txt", LocalPath: filepath.Join(localDir, "b.txt"), ArtifactoryPath: "repo/dir/b.txt"}, plan.Actions[0])
+		assert.Equal(t, SyncDelete, plan.Actions[1].Type)
+		assert.Equal(t, "repo/dir/c.txt", plan.Actions[1].ArtifactoryPath)
+		assert.Equal(t, SyncAdd, plan.Actions[2].Type)
+		assert.Equal(t, "repo/dir/d/e.txt", plan.Actions[2].ArtifactoryPath)
+	}
+	assert.Empty(t, uploaded)
+	assert.Empty(t, deleted)
+
+	syncCommand = newSyncCommand()
+	syncCommand.SetQuiet(true)
+	assert.NoError(t, syncCommand.Run())
+	assert.Equal(t, 3, syncCommand.Result().SuccessCount())
+	assert.Equal(t, 0, syncCommand.Result().FailCount())
+	sort.Strings(uploaded)
+	assert.Equal(t, []string{"/repo/dir/b.txt", "/repo/dir/d/e.txt"}, uploaded)
+	assert.Equal(t, []string{"/repo/dir/c.txt"}, deleted)
+}
+
+func TestSyncCommandInvalidTarget(t *testing.T) {
+	assert.Error(t, NewSyncCommand().SetLocalPath(t.TempDir()).SetTargetPath("repo/*/").Run())
+}
+
+func TestSyncCommandSpecialCharacters(t *testing.T) {
+	// The special characters in the local directory itself mustn't be treated as a pattern either.
+	localDir := filepath.Join(t.TempDir(), "c++ (1)")
+	localFiles := []string{"a(1).txt", "b?.bin", "c{1}.txt", "d*.txt", "d.txt", "v1.2/e+f.txt", "v1.0 (rc)/g.txt", "v1.0 (rc)/h*.txt", "v1.0 (rc)/h.txt"}
+	for _, relativePath := range localFiles {
+		localPath := filepath.Join(localDir, filepath.FromSlash(relativePath))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0700))
+		assert.NoError(t, os.WriteFile(localPath, []byte(relativePath), 0600))
+	}
+	var mutex sync.Mutex
+	var uploaded []string
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/system/version":
+			_, err := w.Write([]byte(`{"version":"7.50.0"}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/api/search/aql":
+			_, err := w.Write([]byte(`{"results":[]}`))
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut:
+			uploaded = append(uploaded, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	syncCommand := NewSyncCommand().SetLocalPath(localDir).SetTargetPath("repo/dir").SetThreads(1)
+	syncCommand.SetServerDetails(serverDetails)
+	assert.NoError(t, syncCommand.Run())
+	assert.Equal(t, len(localFiles), syncCommand.Result().SuccessCount())
+	assert.Equal(t, 0, syncCommand.Result().FailCount())
+	// Each file is uploaded exactly once, to its own path.
+	sort.Strings(uploaded)
+	assert.Equal(t, []string{"/repo/dir/a(1).txt", "/repo/dir/b?.bin", "/repo/dir/c{1}.txt", "/repo/dir/d*.txt", "/repo/dir/d.txt", "/repo/dir/v1.0 (rc)/g.txt", "/repo/dir/v1.0 (rc)/h*.txt", "/repo/dir/v1.0 (rc)/h.txt", "/repo/dir/v1.2/e+f.txt"}, uploaded)
+}
@@ -6,9 +6,13 @@ import (
 	buildInfo "github.com/jfrog/build-info-go/entities"
 
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
@@ -25,6 +29,12 @@ type UploadCommand struct {
 	uploadConfiguration *utils.UploadConfiguration
 	buildConfiguration  *utils.BuildConfiguration
 	progress            ioUtils.ProgressMgr
+	// When true, the files are published only if all of them are uploaded successfully.
+	atomic bool
+	// When true, the files which already exist in Artifactory are deployed by checksum, instead of being transferred.
+	deduplicate bool
+	// When true, the command's result is printed as a formats.GenericCommandOutput JSON.
+	structuredOutput bool
 }
 
 func NewUploadCommand() *UploadCommand {
@@ -45,6 +55,35 @@ func (uc *UploadCommand) SetUploadConfiguration(uploadConfiguration *utils.Uploa
 	return uc
 }
 
+func (uc *UploadCommand) Atomic() bool {
+	return uc.atomic
+}
+
+// In an atomic upload, the files are staged in a '.jfrog-staging' folder at the root of each target repository, before being moved into place.
+// Note that the staged files are regular artifacts, so they're visible to the repository's consumers, webhooks and indexers during the upload.
+func (uc *UploadCommand) SetAtomic(atomic bool) *UploadCommand {
+	uc.atomic = atomic
+	return uc
+}
+
+func (uc *UploadCommand) Deduplicate() bool {
+	return uc.deduplicate
+}
+
+func (uc *UploadCommand) SetDeduplicate(deduplicate bool) *UploadCommand {
+	uc.deduplicate = deduplicate
+	return uc
+}
+
+func (uc *UploadCommand) StructuredOutput() bool {
+	return uc.structuredOutput
+}
+
+func (uc *UploadCommand) SetStructuredOutput(structuredOutput bool) *UploadCommand {
+	uc.structuredOutput = structuredOutput
+	return uc
+}
+
 func (uc *UploadCommand) SetProgress(progress ioUtils.ProgressMgr) {
 	uc.progress = progress
 }
@@ -62,7 +101,36 @@ func (uc *UploadCommand) CommandName() string {
 }
 
 func (uc *UploadCommand) Run() error {
-	return uc.upload()
+	if !uc.structuredOutput {
+		return uc.upload()
+	}
+	startTime := time.Now()
+	err := uc.upload()
+	return uc.printCommandOutput(time.Since(startTime), err)
+}
+
+// Prints the upload's result as JSON, and returns the upload's resulting error.
+func (uc *UploadCommand) printCommandOutput(duration time.Duration, runErr error) (err error) {
+	err = runErr
+	serverId := ""
+	if uc.serverDetails != nil {
+		serverId = uc.serverDetails.ServerId
+	}
+	output, e := commandsutils.NewGenericCommandOutput(uc.CommandName(), serverId, duration, uc.result, runErr)
+	if e == nil {
+		e = commandsutils.PrintCommandOutput(output)
+	}
+	// The transfer details reader was kept only for the output, unless a detailed summary was requested.
+	if reader := uc.result.Reader(); reader != nil && !uc.DetailedSummary() {
+		uc.result.SetReader(nil)
+		if closeErr := reader.Close(); e == nil {
+			e = closeErr
+		}
+	}
+	if err == nil {
+		err = e
+	}
+	return
 }
 
 // Uploads the artifacts in the specified local path pattern to the specified target path.
@@ -125,12 +193,32 @@ func (uc *UploadCommand) upload() (err error) {
 		uploadParamsArray = append(uploadParamsArray, uploadParams)
 	}
 
+	var checksumDeployedFiles map[string]int64
+	if uc.deduplicate {
+		uploadParamsArray, checksumDeployedFiles, err = uc.deduplicateUploads(servicesManager, uploadParamsArray)
+		if err != nil {
+			return
+		}
+	}
+
+	// In an atomic upload, the files are staged first, and moved into place only after all of them were uploaded.
+	var transaction *uploadTransaction
+	if uc.atomic && !uc.DryRun() {
+		transaction = newUploadTransaction()
+		for i := range uploadParamsArray {
+			uploadParamsArray[i].Target, err = transaction.stageTarget(uploadParamsArray[i].Target)
+			if err != nil {
+				return
+			}
+		}
+	}
+
 	// Perform upload.
-	// In case of build-info collection or a detailed summary request, we use the upload service which provides results file reader,
+	// In case of build-info collection, a detailed summary or structured output request or files deployed by checksum, we use the upload service which provides results file reader,
 	// otherwise we use the upload service which provides only general counters.
 	var successCount, failCount int
 	var artifactsDetailsReader *content.ContentReader = nil
-	if uc.DetailedSummary() || toCollect {
+	if uc.DetailedSummary() || uc.structuredOutput || toCollect || len(checksumDeployedFiles) > 0 {
 		var summary *rtServicesUtils.OperationSummary
 		summary, err = servicesManager.UploadFilesWithSummary(uploadParamsArray...)
 		if err != nil {
@@ -145,10 +233,28 @@ func (uc *UploadCommand) upload() (err error) {
 					err = e
 				}
 			}()
-			// If 'detailed summary' was requested, then the reader should not be closed here.
-			// It will be closed after it will be used to generate the summary.
-			if uc.DetailedSummary() {
-				uc.result.SetReader(summary.TransferDetailsReader)
+			if len(checksumDeployedFiles) > 0 {
+				// Only the files which were uploaded successfully saved their transfer.
+				bytesSaved, e := calcBytesSaved(summary.TransferDetailsReader, checksumDeployedFiles)
+				if e != nil {
+					errorOccurred = true
+					log.Error(e)
+				}
+				uc.result.SetBytesSaved(bytesSaved)
+			}
+			// If 'detailed summary' or structured output was requested, then the reader should not be closed here.
+			// It will be closed after it will be used to generate the summary or the output.
+			if uc.DetailedSummary() || uc.structuredOutput {
+				transferDetailsReader := summary.TransferDetailsReader
+				if transaction != nil {
+					transferDetailsReader, err = transaction.convertTransferDetails(transferDetailsReader)
+				}
+				if err == nil {
+					uc.result.SetReader(transferDetailsReader)
+				} else {
+					errorOccurred = true
+					log.Error(err)
+				}
 			} else {
 				err = summary.TransferDetailsReader.Close()
 				if err != nil {
@@ -168,6 +274,11 @@ func (uc *UploadCommand) upload() (err error) {
 	}
 	uc.result.SetSuccessCount(successCount)
 	uc.result.SetFailCount(failCount)
+	if transaction != nil {
+		if err = uc.completeUploadTransaction(transaction, errorOccurred || failCount > 0); err != nil {
+			return
+		}
+	}
 	if errorOccurred {
 		err = errors.New("upload finished with errors. Review the logs for more information")
 		return
@@ -191,6 +302,11 @@ func (uc *UploadCommand) upload() (err error) {
 		if err != nil {
 			return
 		}
+		if transaction != nil {
+			for i := range buildArtifacts {
+				buildArtifacts[i].Path = transaction.committedPath(buildArtifacts[i].Path)
+			}
+		}
 		return utils.PopulateBuildArtifactsAsPartials(buildArtifacts, uc.buildConfiguration, buildInfo.Generic)
 	}
 	return
@@ -260,6 +376,51 @@ func getUploadParams(f *spec.File, configuration *utils.UploadConfiguration, bui
 	return
 }
 
+// Sets the pattern of the upload params, so that only the provided local file is uploaded, even if its path includes characters such as '*', '(' or '+'.
+// A pattern which is the path of an existing file is uploaded as a single file, without being matched against other files or listing its directory.
+// This holds in the wildcard mode if the path includes no wildcards or placeholder parentheses, and in the regexp mode if it includes no parentheses.
+// Other paths are uploaded by a regexp, which is searched from the last directory whose name needs no escaping.
+func setExactUploadPattern(uploadParams *services.UploadParams, localPath string) error {
+	uploadParams.Ant = false
+	uploadParams.Recursive = false
+	switch {
+	case !strings.Contains(localPath, "*") && len(clientUtils.CreateParenthesesSlice(localPath, uploadParams.Target).Parentheses) == 0:
+		uploadParams.Pattern = localPath
+		uploadParams.Regexp = false
+	case !strings.Contains(localPath, "("):
+		uploadParams.Pattern = localPath
+		uploadParams.Regexp = true
+	default:
+		// The path is made absolute, so that the regexp can't match the same relative path under another directory.
+		absPath, err := filepath.Abs(localPath)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		uploadParams.Pattern, uploadParams.Recursive = createExactUploadRegexp(absPath)
+		uploadParams.Regexp = true
+	}
+	return nil
+}
+
+// Returns a regular expression which matches only the provided absolute path, and whether the directories under its root path should be searched.
+// The whole path is escaped, but a group is opened at the first section which is changed by escaping, since the root path the search starts from precedes it.
+func createExactUploadRegexp(absPath string) (pattern string, recursive bool) {
+	sections := strings.Split(absPath, string(filepath.Separator))
+	for i, section := range sections {
+		escaped := regexp.QuoteMeta(section)
+		if escaped != section || i == len(sections)-1 {
+			// Regular expressions on Windows use escaped separators.
+			pattern = strings.Join(sections[:i], regexp.QuoteMeta(string(filepath.Separator)))
+			if i > 0 {
+				pattern += regexp.QuoteMeta(string(filepath.Separator))
+			}
+			rest := strings.Join(sections[i:], string(filepath.Separator))
+			return pattern + "(?:" + regexp.QuoteMeta(rest) + ")$", i < len(sections)-1
+		}
+	}
+	return
+}
+
 func (uc *UploadCommand) handleSyncDeletes(syncDeletesProp string) (err error) {
 	servicesManager, err := utils.CreateServiceManager(uc.serverDetails, uc.retries, uc.retryWaitTimeMilliSecs, false)
 	if err != nil {
@@ -0,0 +1,81 @@
+package generic
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	coretests "github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadStructuredOutput(t *testing.T) {
+	localDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "a.txt"), []byte("content"), 0600))
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	serverDetails.ServerId = "my-server"
+
+	buffer, _, previousLog := coretests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+
+	uploadSpec := spec.NewBuilder().Pattern(filepath.Join(localDir, "*.txt")).Target("repo/path/").Flat(true).BuildSpec()
+	uploadCommand := NewUploadCommand().SetUploadConfiguration(&utils.UploadConfiguration{Threads: 1}).SetStructuredOutput(true)
+	uploadCommand.SetServerDetails(serverDetails).SetSpec(uploadSpec)
+	assert.NoError(t, uploadCommand.Run())
+	// The transfer details reader was used only for the output, so it's closed after the output is printed.
+	assert.Nil(t, uploadCommand.Result().Reader())
+
+	var output formats.GenericCommandOutput
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &output))
+	assert.Equal(t, "rt_upload", output.Command)
+	assert.Equal(t, "my-server", output.ServerId)
+	assert.Equal(t, formats.CommandOutputStatusSuccess, output.Status)
+	assert.Equal(t, formats.OutputTotals{Success: 1}, output.Totals)
+	if assert.Len(t, output.Items, 1) {
+		assert.Equal(t, filepath.Join(localDir, "a.txt"), output.Items[0].SourcePath)
+		assert.Contains(t, output.Items[0].TargetPath, "repo/path/a.txt")
+	}
+}
+
+func TestCreateExactUploadRegexp(t *testing.T) {
+	separator := string(filepath.Separator)
+	escapedSeparator := regexp.QuoteMeta(separator)
+	root := filepath.Join(separator+"root", "dir")
+	escapedRoot := strings.ReplaceAll(root, separator, escapedSeparator)
+	testCases := []struct {
+		relativePath      string
+		expectedPattern   string
+		expectedRecursive bool
+	}{
+		{"d*.txt", escapedRoot + escapedSeparator + `(?:d\*\.txt)$`, false},
+		{filepath.Join("c++ (1)", "d*.txt"), escapedRoot + escapedSeparator + `(?:c\+\+ \(1\)` + escapedSeparator + `d\*\.txt)$`, true},
+		{filepath.Join("sub", "v1.0 (rc)", "d*.txt"), escapedRoot + escapedSeparator + "sub" + escapedSeparator + `(?:v1\.0 \(rc\)` + escapedSeparator + `d\*\.txt)$`, true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.relativePath, func(t *testing.T) {
+			localPath := filepath.Join(root, testCase.relativePath)
+			pattern, recursive := createExactUploadRegexp(localPath)
+			assert.Equal(t, testCase.expectedPattern, pattern)
+			assert.Equal(t, testCase.expectedRecursive, recursive)
+			// The pattern matches only the exact path.
+			assert.True(t, regexp.MustCompile(pattern).MatchString(localPath))
+			assert.False(t, regexp.MustCompile(pattern).MatchString(localPath+"x"))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package generic
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"path/filepath"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	rtServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// A local file, matched by the upload spec, and the checksum it's looked up by.
+type deduplicatedFile struct {
+	uploadParams *services.UploadParams
+	localPath    string
+	targetPath   string
+	sha256       string
+	size         int64
+}
+
+// The maximum number of checksums looked up by a single AQL query.
+const checksumsAqlQueryChunkSize = 500
+
+// In a deduplicated upload, the checksums of all the files to upload are looked up in Artifactory using AQL queries, before the upload starts.
+// The files which already exist anywhere in Artifactory are deployed by checksum, so Artifactory copies the binaries it already stores, without transferring their content.
+// The rest of the files are uploaded without trying to deploy them by checksum first.
+// To do so, the upload params are split into params per file. Params which can't be split, such as archives, are returned as is.
+// Also returns the sizes of the files which are deployed by checksum, mapped by their local paths.
+func (uc *UploadCommand) deduplicateUploads(servicesManager artifactory.ArtifactoryServicesManager, uploadParamsArray []services.UploadParams) ([]services.UploadParams, map[string]int64, error) {
+	log.Info("Calculating the checksums of the files to upload...")
+	var files []*deduplicatedFile
+	var deduplicatedParams []services.UploadParams
+	for i := range uploadParamsArray {
+		if !isDeduplicable(uploadParamsArray[i]) {
+			deduplicatedParams = append(deduplicatedParams, uploadParamsArray[i])
+			continue
+		}
+		paramsFiles, err := collectDeduplicatedFiles(&uploadParamsArray[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, paramsFiles...)
+	}
+	if len(files) == 0 {
+		return deduplicatedParams, nil, nil
+	}
+	existingChecksums, err := searchExistingChecksums(servicesManager, files)
+	if err != nil {
+		return nil, nil, err
+	}
+	checksumDeployedFiles := make(map[string]int64)
+	var existingSize int64
+	for _, file := range files {
+		fileParams := services.DeepCopyUploadParams(file.uploadParams)
+		fileParams.Target = file.targetPath
+		fileParams.Exclusions = nil
+		fileParams.Flat = true
+		if err = setExactUploadPattern(&fileParams, file.localPath); err != nil {
+			return nil, nil, err
+		}
+		if existingChecksums[file.sha256] {
+			fileParams.MinChecksumDeploy = 0
+			checksumDeployedFiles[getChecksumDeployedFileKey(file.localPath)] = file.size
+			existingSize += file.size
+		} else {
+			fileParams.MinChecksumDeploy = math.MaxInt64
+		}
+		deduplicatedParams = append(deduplicatedParams, fileParams)
+	}
+	log.Info(len(checksumDeployedFiles), "of the", len(files), "files already exist in Artifactory and are deployed by checksum, which may save the transfer of", utils.ConvertIntToStorageSizeString(existingSize)+".")
+	return deduplicatedParams, checksumDeployedFiles, nil
+}
+
+// Returns the total size of the files which were deployed by checksum successfully. The provided reader of the transfer details is reset.
+func calcBytesSaved(transferDetailsReader *content.ContentReader, checksumDeployedFiles map[string]int64) (bytesSaved int64, err error) {
+	defer transferDetailsReader.Reset()
+	for transferDetails := new(clientUtils.FileTransferDetails); transferDetailsReader.NextRecord(transferDetails) == nil; transferDetails = new(clientUtils.FileTransferDetails) {
+		bytesSaved += checksumDeployedFiles[getChecksumDeployedFileKey(transferDetails.SourcePath)]
+	}
+	return bytesSaved, transferDetailsReader.GetError()
+}
+
+// Files may be uploaded by their absolute paths, so the sizes of the files which are deployed by checksum are mapped by their absolute paths.
+func getChecksumDeployedFileKey(localPath string) string {
+	if absPath, err := filepath.Abs(localPath); err == nil {
+		return absPath
+	}
+	return filepath.Clean(localPath)
+}
+
+// Archives and exploded archives are uploaded differently than the files they're created from, and symlinks and directories have no checksums.
+func isDeduplicable(uploadParams services.UploadParams) bool {
+	return uploadParams.Archive == "" && !uploadParams.IsExplodeArchive() && !uploadParams.IsSymlink() && !uploadParams.IsIncludeDirs()
+}
+
+// Returns the files matched by the upload params, with their target paths and checksums.
+func collectDeduplicatedFiles(uploadParams *services.UploadParams) ([]*deduplicatedFile, error) {
+	var files []*deduplicatedFile
+	// Only the paths are needed, so the VCS properties aren't collected.
+	collectParams := services.DeepCopyUploadParams(uploadParams)
+	collectParams.AddVcsProps = false
+	err := services.CollectFilesForUpload(collectParams, nil, clientUtils.NewVcsDetails(), func(data services.UploadData) {
+		if !data.IsDir {
+			files = append(files, &deduplicatedFile{uploadParams: uploadParams, localPath: data.Artifact.LocalPath, targetPath: data.Artifact.TargetPath})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		details, err := fileutils.GetFileDetails(file.localPath, true)
+		if err != nil {
+			return nil, err
+		}
+		file.sha256 = details.Checksum.Sha256
+		file.size = details.Size
+	}
+	return files, nil
+}
+
+// Returns the SHA256 checksums of the files which exist in Artifactory, in any repository.
+func searchExistingChecksums(servicesManager artifactory.ArtifactoryServicesManager, files []*deduplicatedFile) (map[string]bool, error) {
+	var checksums []string
+	existingChecksums := make(map[string]bool)
+	for _, file := range files {
+		if _, added := existingChecksums[file.sha256]; !added {
+			existingChecksums[file.sha256] = false
+			checksums = append(checksums, file.sha256)
+		}
+	}
+	for start := 0; start < len(checksums); start += checksumsAqlQueryChunkSize {
+		end := start + checksumsAqlQueryChunkSize
+		if end > len(checksums) {
+			end = len(checksums)
+		}
+		if err := searchExistingChecksumsChunk(servicesManager, checksums[start:end], existingChecksums); err != nil {
+			return nil, err
+		}
+	}
+	return existingChecksums, nil
+}
+
+func searchExistingChecksumsChunk(servicesManager artifactory.ArtifactoryServicesManager, checksums []string, existingChecksums map[string]bool) (err error) {
+	query, err := createChecksumsAqlQuery(checksums)
+	if err != nil {
+		return
+	}
+	reader, err := servicesManager.Aql(query)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	respBody, err := io.ReadAll(reader)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	result := new(rtServicesUtils.AqlSearchResult)
+	if err = json.Unmarshal(respBody, result); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for _, item := range result.Results {
+		existingChecksums[item.Sha256] = true
+	}
+	return
+}
+
+func createChecksumsAqlQuery(checksums []string) (string, error) {
+	var conditions []map[string]string
+	for _, checksum := range checksums {
+		conditions = append(conditions, map[string]string{"sha256": checksum})
+	}
+	criteria, err := json.Marshal(map[string]interface{}{"$or": conditions})
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return `items.find(` + string(criteria) + `).include("sha256")`, nil
+}
@@ -0,0 +1,147 @@
+package generic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	rtServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicatedUpload(t *testing.T) {
+	// The files are uploaded by their exact paths, which mustn't be treated as patterns.
+	localDir := filepath.Join(t.TempDir(), "c++ (1)")
+	assert.NoError(t, os.Mkdir(localDir, 0700))
+	localFiles := map[string]string{"existing.txt": "existing content", "new.txt": "new content"}
+	for name, fileContent := range localFiles {
+		assert.NoError(t, os.WriteFile(filepath.Join(localDir, name), []byte(fileContent), 0600))
+	}
+	// The existing file is stored in another repository.
+	existingItem := createTestResultItem("other.txt", localFiles["existing.txt"])
+	existingItem.Repo = "other-repo"
+
+	var mutex sync.Mutex
+	var aqlQueries []string
+	checksumDeploys := make(map[string]bool)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/search/aql":
+			query, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			aqlQueries = append(aqlQueries, string(query))
+			content, err := json.Marshal(map[string]interface{}{"results": []rtServicesUtils.ResultItem{existingItem}})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut:
+			isChecksumDeploy := r.Header.Get("X-Checksum-Deploy") == "true"
+			checksumDeploys[strings.Split(r.URL.Path, ";")[0]] = isChecksumDeploy
+			if isChecksumDeploy && r.Header.Get("X-Checksum") != existingItem.Sha256 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	uploadSpec := spec.NewBuilder().Pattern(filepath.Join(localDir, "*.txt")).Target("repo/path/").Flat(true).BuildSpec()
+	uploadCommand := NewUploadCommand().SetUploadConfiguration(&utils.UploadConfiguration{Threads: 1}).SetDeduplicate(true)
+	uploadCommand.SetServerDetails(serverDetails).SetSpec(uploadSpec)
+	assert.NoError(t, uploadCommand.Run())
+	assert.Equal(t, 2, uploadCommand.Result().SuccessCount())
+	assert.Equal(t, 0, uploadCommand.Result().FailCount())
+	assert.Equal(t, int64(len(localFiles["existing.txt"])), uploadCommand.Result().BytesSaved())
+
+	// The checksums of all the files are looked up using a single query.
+	if assert.Len(t, aqlQueries, 1) {
+		assert.Contains(t, aqlQueries[0], existingItem.Sha256)
+		assert.Contains(t, aqlQueries[0], createTestResultItem("new.txt", localFiles["new.txt"]).Sha256)
+	}
+	// Only the existing file is deployed by checksum, and the new file is transferred without trying to deploy it by checksum first.
+	assert.Equal(t, map[string]bool{"/repo/path/existing.txt": true, "/repo/path/new.txt": false}, checksumDeploys)
+}
+
+func TestDeduplicatedUploadFailure(t *testing.T) {
+	localDir := t.TempDir()
+	// The file name includes characters which are special in wildcard patterns.
+	fileName := "existing(1)?.txt"
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, fileName), []byte("existing content"), 0600))
+	existingItem := createTestResultItem("other.txt", "existing content")
+
+	var mutex sync.Mutex
+	var deployed []string
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.URL.Path == "/api/search/aql":
+			content, err := json.Marshal(map[string]interface{}{"results": []rtServicesUtils.ResultItem{existingItem}})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut:
+			deployed = append(deployed, strings.Split(r.URL.Path, ";")[0])
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	uploadSpec := spec.NewBuilder().Pattern(filepath.Join(localDir, "*.txt")).Target("repo/path/").Flat(true).BuildSpec()
+	uploadCommand := NewUploadCommand().SetUploadConfiguration(&utils.UploadConfiguration{Threads: 1}).SetDeduplicate(true)
+	uploadCommand.SetServerDetails(serverDetails).SetSpec(uploadSpec)
+	uploadCommand.SetRetries(0)
+	assert.Error(t, uploadCommand.Run())
+	assert.Equal(t, 0, uploadCommand.Result().SuccessCount())
+	assert.Equal(t, 1, uploadCommand.Result().FailCount())
+	// The file was deployed by checksum, but since the deployment failed, no transfer was saved.
+	assert.Contains(t, deployed, "/repo/path/"+fileName)
+	assert.Zero(t, uploadCommand.Result().BytesSaved())
+}
+
+func TestSearchExistingChecksumsChunks(t *testing.T) {
+	var files []*deduplicatedFile
+	for i := 0; i < checksumsAqlQueryChunkSize+1; i++ {
+		files = append(files, &deduplicatedFile{sha256: strconv.Itoa(i)})
+	}
+	// Duplicate checksums are looked up once.
+	files = append(files, &deduplicatedFile{sha256: "0"})
+
+	var queries []string
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		queries = append(queries, string(query))
+		_, err = w.Write([]byte(`{"results":[{"sha256":"1"}]}`))
+		assert.NoError(t, err)
+	})
+	defer testServer.Close()
+	servicesManager, err := utils.CreateServiceManager(serverDetails, 0, 0, false)
+	assert.NoError(t, err)
+
+	existingChecksums, err := searchExistingChecksums(servicesManager, files)
+	assert.NoError(t, err)
+	if assert.Len(t, queries, 2) {
+		assert.Equal(t, checksumsAqlQueryChunkSize, strings.Count(queries[0], `{"sha256"`))
+		assert.Equal(t, 1, strings.Count(queries[1], `{"sha256"`))
+	}
+	assert.Len(t, existingChecksums, checksumsAqlQueryChunkSize+1)
+	assert.True(t, existingChecksums["1"])
+	assert.False(t, existingChecksums["0"])
+}
@@ -0,0 +1,281 @@
+package generic
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	rtServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+// The folder, at the root of each target repository, in which the files of atomic uploads are staged. It is deleted once no upload uses it.
+const uploadStagingDir = ".jfrog-staging"
+
+// In an atomic upload, the files are uploaded to a staging folder in each of the target repositories.
+// Only when all the files are uploaded successfully, they are moved into place. Otherwise, the staged files are deleted.
+type uploadTransaction struct {
+	id string
+	// The repositories the files are staged in.
+	repos []string
+}
+
+func newUploadTransaction() *uploadTransaction {
+	return &uploadTransaction{id: strconv.FormatInt(time.Now().UnixNano(), 10)}
+}
+
+// Returns the path of the transaction's staging folder, relative to the repository.
+func (ut *uploadTransaction) stagingPath() string {
+	return path.Join(uploadStagingDir, ut.id)
+}
+
+// Returns the target the files are staged at, instead of being uploaded to the provided target.
+// For example, the 'repo/path/' target is staged at 'repo/.jfrog-staging/<id>/path/'.
+func (ut *uploadTransaction) stageTarget(target string) (string, error) {
+	repo, pathInRepo, _ := strings.Cut(target, "/")
+	if repo == "" || strings.ContainsAny(repo, "*{}") {
+		return "", errorutils.CheckErrorf("atomic upload requires the target repository to be explicitly specified, but '%s' was found", target)
+	}
+	if !ut.isStagedIn(repo) {
+		ut.repos = append(ut.repos, repo)
+	}
+	return repo + "/" + ut.stagingPath() + "/" + pathInRepo, nil
+}
+
+func (ut *uploadTransaction) isStagedIn(repo string) bool {
+	for _, stagedRepo := range ut.repos {
+		if stagedRepo == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the path a staged file is moved to.
+// The path may be a full URL, a path which includes the repository or a path relative to the repository.
+func (ut *uploadTransaction) committedPath(stagedPath string) string {
+	stagingPath := ut.stagingPath() + "/"
+	if strings.HasPrefix(stagedPath, stagingPath) {
+		return strings.TrimPrefix(stagedPath, stagingPath)
+	}
+	return strings.Replace(stagedPath, "/"+stagingPath, "/", 1)
+}
+
+// Moves the staged files into place, using the move service, and removes the staging folders.
+// expectedCount is the number of staged files. Fewer moved files means that some of the staged files are missing.
+func (ut *uploadTransaction) commit(servicesManager artifactory.ArtifactoryServicesManager, expectedCount int) error {
+	var moveParamsArray []services.MoveCopyParams
+	for _, repo := range ut.repos {
+		moveParams := services.NewMoveCopyParams()
+		moveParams.Pattern = repo + "/" + ut.stagingPath() + "/(*)"
+		moveParams.Target = repo + "/{1}"
+		moveParams.Recursive = true
+		moveParamsArray = append(moveParamsArray, moveParams)
+	}
+	movedCount, failedCount, err := servicesManager.Move(moveParamsArray...)
+	if err != nil {
+		return err
+	}
+	if failedCount > 0 {
+		return errorutils.CheckErrorf("%d of the %d staged files could not be moved into place", failedCount, movedCount+failedCount)
+	}
+	if movedCount < expectedCount {
+		return errorutils.CheckErrorf("only %d of the %d staged files were found in the staging folder and moved into place", movedCount, expectedCount)
+	}
+	return ut.removeStagingFolders(servicesManager)
+}
+
+// Deletes the staged files.
+func (ut *uploadTransaction) rollback(servicesManager artifactory.ArtifactoryServicesManager) error {
+	return ut.removeStagingFolders(servicesManager)
+}
+
+func (ut *uploadTransaction) removeStagingFolders(servicesManager artifactory.ArtifactoryServicesManager) (err error) {
+	if len(ut.repos) == 0 {
+		return
+	}
+	// A file group which matched no files doesn't create a staging folder in its repository, so only the existing folders are deleted.
+	stagedRepos, err := ut.searchStagingFolders(servicesManager)
+	if err != nil || len(stagedRepos) == 0 {
+		return
+	}
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return
+	}
+	for _, repo := range stagedRepos {
+		writer.Write(rtServicesUtils.ResultItem{Repo: repo, Path: uploadStagingDir, Name: ut.id, Type: "folder"})
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	deletedCount, err := servicesManager.DeleteFiles(reader)
+	if err != nil {
+		return
+	}
+	if deletedCount < len(stagedRepos) {
+		err = errorutils.CheckErrorf("failed to delete the '%s' staging folder", ut.stagingPath())
+		return
+	}
+	// The files are already in place or deleted, so failing to remove the parent staging folder doesn't fail the upload.
+	if e := ut.removeEmptyStagingDirs(servicesManager, stagedRepos); e != nil {
+		log.Warn("Couldn't delete the empty '" + uploadStagingDir + "' folders: " + e.Error())
+	}
+	return
+}
+
+// Deletes the '.jfrog-staging' folder of the provided repositories, unless it stages the files of other uploads.
+// An upload which starts staging its files after the check fails to commit, since its staged files are missing.
+func (ut *uploadTransaction) removeEmptyStagingDirs(servicesManager artifactory.ArtifactoryServicesManager, stagedRepos []string) (err error) {
+	nonEmptyRepos, err := searchRepos(servicesManager, stagedRepos, map[string]interface{}{"type": "any", "path": uploadStagingDir})
+	if err != nil {
+		return
+	}
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return
+	}
+	emptyCount := 0
+	for _, repo := range stagedRepos {
+		if slices.Contains(nonEmptyRepos, repo) {
+			continue
+		}
+		writer.Write(rtServicesUtils.ResultItem{Repo: repo, Path: ".", Name: uploadStagingDir, Type: "folder"})
+		emptyCount++
+	}
+	if err = writer.Close(); err != nil || emptyCount == 0 {
+		return
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	_, err = servicesManager.DeleteFiles(reader)
+	return
+}
+
+// Returns the repositories in which the transaction's staging folder exists.
+func (ut *uploadTransaction) searchStagingFolders(servicesManager artifactory.ArtifactoryServicesManager) ([]string, error) {
+	return searchRepos(servicesManager, ut.repos, map[string]interface{}{"type": "folder", "path": uploadStagingDir, "name": ut.id})
+}
+
+// Returns the repositories, out of the provided repositories, which include items matching the AQL criteria.
+func searchRepos(servicesManager artifactory.ArtifactoryServicesManager, repos []string, criteria map[string]interface{}) (foundRepos []string, err error) {
+	query, err := createReposAqlQuery(repos, criteria)
+	if err != nil {
+		return
+	}
+	reader, err := servicesManager.Aql(query)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	respBody, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	result := new(rtServicesUtils.AqlSearchResult)
+	if err = json.Unmarshal(respBody, result); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, item := range result.Results {
+		if !slices.Contains(foundRepos, item.Repo) {
+			foundRepos = append(foundRepos, item.Repo)
+		}
+	}
+	return
+}
+
+func createReposAqlQuery(repos []string, criteria map[string]interface{}) (string, error) {
+	var repoConditions []map[string]string
+	for _, repo := range repos {
+		repoConditions = append(repoConditions, map[string]string{"repo": repo})
+	}
+	criteria["$or"] = repoConditions
+	body, err := json.Marshal(criteria)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return `items.find(` + string(body) + `).include("repo","path","name")`, nil
+}
+
+// Returns a reader of the transfer details, with the paths the staged files are moved to. The provided reader is closed.
+func (ut *uploadTransaction) convertTransferDetails(reader *content.ContentReader) (convertedReader *content.ContentReader, err error) {
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return
+	}
+	for transferDetails := new(clientUtils.FileTransferDetails); reader.NextRecord(transferDetails) == nil; transferDetails = new(clientUtils.FileTransferDetails) {
+		transferDetails.TargetPath = ut.committedPath(transferDetails.TargetPath)
+		writer.Write(*transferDetails)
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+	if err = reader.GetError(); err != nil {
+		return
+	}
+	if writer.IsEmpty() {
+		return content.NewEmptyContentReader(content.DefaultKey), nil
+	}
+	return content.NewContentReader(writer.GetFilePath(), content.DefaultKey), nil
+}
+
+// Moves the staged files into place if all the files were uploaded successfully, or deletes them otherwise.
+func (uc *UploadCommand) completeUploadTransaction(transaction *uploadTransaction, uploadFailed bool) error {
+	servicesManager, err := utils.CreateServiceManagerWithThreads(uc.serverDetails, false, uc.uploadConfiguration.Threads, uc.retries, uc.retryWaitTimeMilliSecs)
+	if err != nil {
+		return err
+	}
+	if uploadFailed {
+		// None of the files are published.
+		uc.result.SetFailCount(uc.result.SuccessCount() + uc.result.FailCount())
+		uc.result.SetSuccessCount(0)
+		uc.result.SetBytesSaved(0)
+		log.Info("Deleting the staged files, since not all the files were uploaded successfully...")
+		if err = transaction.rollback(servicesManager); err != nil {
+			return errorutils.CheckErrorf("atomic upload failed and the staged files could not be deleted from the '%s' folder: %s", transaction.stagingPath(), err.Error())
+		}
+		return errorutils.CheckErrorf("atomic upload failed, no files were published. Review the logs for more information")
+	}
+	log.Info("All the files were uploaded successfully. Moving them from the staging folder into place...")
+	if err = transaction.commit(servicesManager, uc.result.SuccessCount()); err != nil {
+		// Some of the files may have already been moved, so the files which are left in the staging folder are deleted.
+		if rollbackErr := transaction.rollback(servicesManager); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return errorutils.CheckErrorf("atomic upload failed while moving the staged files into place, some of the files may have been published: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package generic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	rtServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadTransactionStageTarget(t *testing.T) {
+	transaction := &uploadTransaction{id: "123"}
+	testCases := []struct {
+		target   string
+		expected string
+	}{
+		{"repo/path/", "repo/.jfrog-staging/123/path/"},
+		{"repo", "repo/.jfrog-staging/123/"},
+		{"repo/", "repo/.jfrog-staging/123/"},
+		{"other/path/{1}.zip", "other/.jfrog-staging/123/path/{1}.zip"},
+	}
+	for _, testCase := range testCases {
+		staged, err := transaction.stageTarget(testCase.target)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, staged)
+	}
+	assert.Equal(t, []string{"repo", "other"}, transaction.repos)
+
+	_, err := transaction.stageTarget("{1}/path/")
+	assert.Error(t, err)
+
+	assert.Equal(t, "path/a.zip", transaction.committedPath(".jfrog-staging/123/path/a.zip"))
+	assert.Equal(t, "repo/path/a.zip", transaction.committedPath("repo/.jfrog-staging/123/path/a.zip"))
+	assert.Equal(t, "http://127.0.0.1/artifactory/repo/a.zip", transaction.committedPath("http://127.0.0.1/artifactory/repo/.jfrog-staging/123/a.zip"))
+}
+
+func TestAtomicUpload(t *testing.T) {
+	localDir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(localDir, name), []byte(name), 0600))
+	}
+	testCases := []struct {
+		name            string
+		failedFile      string
+		zeroMatchGroup  bool
+		concurrent      bool
+		expectedSuccess int
+		expectedFail    int
+		expectedMoves   []string
+	}{
+		{"allUploaded", "", false, false, 2, 0, []string{"repo/path/a.txt", "repo/path/b.txt"}},
+		{"partialFailure", "b.txt", false, false, 0, 2, nil},
+		// A file group which matches no files doesn't create a staging folder in its repository.
+		{"zeroMatchGroup", "", true, false, 2, 0, []string{"repo/path/a.txt", "repo/path/b.txt"}},
+		// The '.jfrog-staging' folder also stages the files of another upload, so it isn't deleted.
+		{"concurrentUpload", "", false, true, 2, 0, []string{"repo/path/a.txt", "repo/path/b.txt"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			var uploaded, moved, deleted []string
+			testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				switch {
+				case r.Method == http.MethodPut:
+					if testCase.failedFile != "" && strings.HasSuffix(r.URL.Path, testCase.failedFile) {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					uploaded = append(uploaded, r.URL.Path)
+					w.WriteHeader(http.StatusCreated)
+				case r.URL.Path == "/api/search/aql":
+					query, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					// Only the 'repo' repository has staged files.
+					var results []rtServicesUtils.ResultItem
+					if strings.Contains(string(query), `"path":".jfrog-staging","type":"any"`) {
+						// The search for the contents of the '.jfrog-staging' folder, after the transaction's staging folder was deleted.
+						if testCase.concurrent {
+							results = []rtServicesUtils.ResultItem{{Repo: "repo", Path: uploadStagingDir, Name: "other", Type: "folder"}}
+						}
+						uploaded = nil
+					}
+					for _, uploadedPath := range uploaded {
+						if !strings.Contains(string(query), `"repo":"repo"`) {
+							break
+						}
+						dir, name := path.Split(strings.TrimPrefix(uploadedPath, "/repo/"))
+						if strings.Contains(string(query), `"type":"folder"`) {
+							// The staging folder search.
+							id := strings.Split(dir, "/")[1]
+							results = []rtServicesUtils.ResultItem{{Repo: "repo", Path: uploadStagingDir, Name: id, Type: "folder"}}
+							break
+						}
+						results = append(results, rtServicesUtils.ResultItem{Repo: "repo", Path: strings.TrimSuffix(dir, "/"), Name: name, Type: "file"})
+					}
+					content, err := json.Marshal(map[string]interface{}{"results": results})
+					assert.NoError(t, err)
+					_, err = w.Write(content)
+					assert.NoError(t, err)
+				case strings.HasPrefix(r.URL.Path, "/api/move/"):
+					moved = append(moved, r.URL.Query().Get("to"))
+				case r.Method == http.MethodDelete:
+					deleted = append(deleted, r.URL.Path)
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			defer testServer.Close()
+
+			uploadSpec := spec.NewBuilder().Pattern(filepath.Join(localDir, "*.txt")).Target("repo/path/").Flat(true).BuildSpec()
+			if testCase.zeroMatchGroup {
+				uploadSpec.Files = append(uploadSpec.Files, spec.File{Pattern: filepath.Join(localDir, "*.none"), Target: "other/path/", Flat: "true"})
+			}
+			uploadCommand := NewUploadCommand().SetUploadConfiguration(&utils.UploadConfiguration{Threads: 1}).SetAtomic(true)
+			uploadCommand.SetServerDetails(serverDetails).SetSpec(uploadSpec)
+			err := uploadCommand.Run()
+			if testCase.failedFile == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "atomic upload failed")
+			}
+			assert.Equal(t, testCase.expectedSuccess, uploadCommand.Result().SuccessCount())
+			assert.Equal(t, testCase.expectedFail, uploadCommand.Result().FailCount())
+			for _, uploadedPath := range uploaded {
+				assert.True(t, strings.HasPrefix(uploadedPath, "/repo/.jfrog-staging/"), uploadedPath)
+			}
+			assert.ElementsMatch(t, testCase.expectedMoves, moved)
+			// The staging folder is deleted in both cases, and its parent is deleted if no other upload uses it.
+			expectedDeleted := 2
+			if testCase.concurrent {
+				expectedDeleted = 1
+			}
+			if assert.Len(t, deleted, expectedDeleted) {
+				assert.Regexp(t, `^/repo/\.jfrog-staging/\d+/$`, deleted[0])
+				if expectedDeleted > 1 {
+					assert.Equal(t, "/repo/.jfrog-staging", deleted[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,460 @@
+package storagereport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type OutputFormat string
+
+const (
+	// OutputFormat values
+	Table OutputFormat = "table"
+	Json  OutputFormat = "json"
+	Csv   OutputFormat = "csv"
+
+	defaultLargestFilesCount = 10
+	// The folder of the files at the root of a repository.
+	rootFolder = "."
+)
+
+var OutputFormats = []string{string(Table), string(Json), string(Csv)}
+
+type StorageReport struct {
+	Created      string            `json:"created"`
+	Repositories []RepositoryUsage `json:"repositories"`
+	LargestFiles []FileUsage       `json:"largestFiles"`
+}
+
+type Usage struct {
+	Files int64 `json:"files"`
+	Size  int64 `json:"size"`
+	// The change since the previous snapshot. Omitted if no snapshot was provided.
+	Growth *UsageGrowth `json:"growth,omitempty"`
+}
+
+type UsageGrowth struct {
+	Files int64 `json:"files"`
+	Size  int64 `json:"size"`
+}
+
+type RepositoryUsage struct {
+	Repo string `json:"repo"`
+	Usage
+	// The usage of each of the repository's top-level folders.
+	Folders []FolderUsage `json:"folders"`
+}
+
+type FolderUsage struct {
+	Path string `json:"path"`
+	Usage
+}
+
+type FileUsage struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+type repositoryRow struct {
+	Repo        string `col-name:"Repository"`
+	Files       string `col-name:"Files"`
+	Size        string `col-name:"Size"`
+	FilesGrowth string `col-name:"Files Growth" extended:"true"`
+	SizeGrowth  string `col-name:"Size Growth" extended:"true"`
+}
+
+type folderRow struct {
+	Repo        string `col-name:"Repository"`
+	Folder      string `col-name:"Folder"`
+	Files       string `col-name:"Files"`
+	Size        string `col-name:"Size"`
+	FilesGrowth string `col-name:"Files Growth" extended:"true"`
+	SizeGrowth  string `col-name:"Size Growth" extended:"true"`
+}
+
+type fileRow struct {
+	Path string `col-name:"Path"`
+	Size string `col-name:"Size"`
+}
+
+// StorageReportCommand reports the storage usage of repositories, and of their top-level folders.
+// The repositories' usage is taken from Artifactory's storage info, and the folders' usage is aggregated from AQL results.
+// Since Artifactory calculates the storage info asynchronously, the repositories' usage may not include the latest changes,
+// and may therefore differ from the total usage of their folders. When no repositories are requested, only the repositories whose storage info
+// was calculated by the time the first of them became available are reported.
+// If a previous report is provided as a snapshot, the growth since it is reported too.
+type StorageReportCommand struct {
+	serverDetails        *config.ServerDetails
+	repos                []string
+	largestFilesCount    int
+	format               OutputFormat
+	previousSnapshotPath string
+	snapshotPath         string
+	report               *StorageReport
+}
+
+func NewStorageReportCommand() *StorageReportCommand {
+	return &StorageReportCommand{largestFilesCount: defaultLargestFilesCount, format: Table}
+}
+
+func (src *StorageReportCommand) SetServerDetails(serverDetails *config.ServerDetails) *StorageReportCommand {
+	src.serverDetails = serverDetails
+	return src
+}
+
+// The keys of the repositories to report. If empty, all the repositories are reported.
+func (src *StorageReportCommand) SetRepos(repos []string) *StorageReportCommand {
+	src.repos = repos
+	return src
+}
+
+func (src *StorageReportCommand) SetLargestFilesCount(largestFilesCount int) *StorageReportCommand {
+	src.largestFilesCount = largestFilesCount
+	return src
+}
+
+func (src *StorageReportCommand) SetFormat(format OutputFormat) *StorageReportCommand {
+	src.format = format
+	return src
+}
+
+// The path of a report saved by a previous run, to calculate the growth since.
+func (src *StorageReportCommand) SetPreviousSnapshotPath(previousSnapshotPath string) *StorageReportCommand {
+	src.previousSnapshotPath = previousSnapshotPath
+	return src
+}
+
+// The path to save the report in, so that it can be used as the previous snapshot of a future run.
+func (src *StorageReportCommand) SetSnapshotPath(snapshotPath string) *StorageReportCommand {
+	src.snapshotPath = snapshotPath
+	return src
+}
+
+// Returns the report created by the last run.
+func (src *StorageReportCommand) Report() *StorageReport {
+	return src.report
+}
+
+func (src *StorageReportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return src.serverDetails, nil
+}
+
+func (src *StorageReportCommand) CommandName() string {
+	return "rt_storage_report"
+}
+
+func (src *StorageReportCommand) Run() (err error) {
+	if src.format != Table && src.format != Json && src.format != Csv {
+		return errorutils.CheckErrorf("only the following output formats are supported: %s", coreutils.ListToText(OutputFormats))
+	}
+	if src.largestFilesCount < 0 {
+		return errorutils.CheckErrorf("the number of largest files to report must not be negative, but %d was found", src.largestFilesCount)
+	}
+	var previousReport *StorageReport
+	if src.previousSnapshotPath != "" {
+		if previousReport, err = loadStorageReport(src.previousSnapshotPath); err != nil {
+			return
+		}
+	}
+	storageInfoManager, err := utils.NewStorageInfoManager(context.Background(), src.serverDetails)
+	if err != nil {
+		return
+	}
+	log.Info("Calculating the storage info...")
+	if err = storageInfoManager.CalculateStorageInfo(); err != nil {
+		return
+	}
+	repoSummaries, err := src.getRepoSummaries(storageInfoManager)
+	if err != nil {
+		return
+	}
+	src.report = &StorageReport{Created: time.Now().Format(time.RFC3339)}
+	for _, repoSummary := range repoSummaries {
+		var repoUsage *RepositoryUsage
+		if repoUsage, err = src.getRepositoryUsage(storageInfoManager, repoSummary); err != nil {
+			return
+		}
+		src.report.Repositories = append(src.report.Repositories, *repoUsage)
+	}
+	sort.Slice(src.report.Repositories, func(i, j int) bool {
+		return src.report.Repositories[i].Size > src.report.Repositories[j].Size
+	})
+	if previousReport != nil {
+		src.report.calculateGrowth(previousReport)
+	}
+	if src.snapshotPath != "" {
+		if err = src.report.save(src.snapshotPath); err != nil {
+			return
+		}
+	}
+	return src.report.print(src.format, previousReport)
+}
+
+// Returns the summaries of the requested repositories, or of all the repositories if none were requested.
+func (src *StorageReportCommand) getRepoSummaries(storageInfoManager *utils.StorageInfoManager) ([]serviceutils.RepositorySummary, error) {
+	var repoSummaries []serviceutils.RepositorySummary
+	if len(src.repos) > 0 {
+		for _, repo := range src.repos {
+			repoSummary, err := storageInfoManager.GetRepoSummary(repo)
+			if err != nil {
+				return nil, err
+			}
+			repoSummaries = append(repoSummaries, *repoSummary)
+		}
+		return repoSummaries, nil
+	}
+	return storageInfoManager.GetReposSummaries()
+}
+
+// Returns the usage of the repository and its top-level folders, and updates the report's largest files.
+func (src *StorageReportCommand) getRepositoryUsage(storageInfoManager *utils.StorageInfoManager, repoSummary serviceutils.RepositorySummary) (repoUsage *RepositoryUsage, err error) {
+	repoUsage = &RepositoryUsage{Repo: repoSummary.RepoKey}
+	if repoUsage.Files, err = utils.GetFilesCountFromRepositorySummary(&repoSummary); err != nil {
+		return
+	}
+	if repoUsage.Size, err = utils.GetUsedSpaceInBytes(&repoSummary); err != nil {
+		return
+	}
+	log.Info("Aggregating the usage of the", repoSummary.RepoKey, "repository folders...")
+	criteria, err := json.Marshal(map[string]string{"repo": repoSummary.RepoKey, "type": "file"})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	reader, err := storageInfoManager.GetServiceManager().Aql(`items.find(` + string(criteria) + `).include("path","name","size")`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := reader.Close()
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	folders := make(map[string]*FolderUsage)
+	err = decodeAqlResults(reader, func(item *serviceutils.ResultItem) {
+		folderPath, _, _ := strings.Cut(item.Path, "/")
+		folder, exists := folders[folderPath]
+		if !exists {
+			folder = &FolderUsage{Path: folderPath}
+			folders[folderPath] = folder
+		}
+		folder.Files++
+		folder.Size += item.Size
+		src.report.addFile(FileUsage{Path: repoSummary.RepoKey + "/" + joinItemPath(item.Path, item.Name), Size: item.Size}, src.largestFilesCount)
+	})
+	for _, folder := range folders {
+		repoUsage.Folders = append(repoUsage.Folders, *folder)
+	}
+	sort.Slice(repoUsage.Folders, func(i, j int) bool {
+		return repoUsage.Folders[i].Size > repoUsage.Folders[j].Size
+	})
+	return
+}
+
+func joinItemPath(itemPath, name string) string {
+	if itemPath == rootFolder {
+		return name
+	}
+	return itemPath + "/" + name
+}
+
+// Decodes the AQL results one at a time, so that the memory usage doesn't depend on the number of results.
+func decodeAqlResults(reader io.Reader, handleItem func(item *serviceutils.ResultItem)) error {
+	decoder := json.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if token == "results" {
+			break
+		}
+	}
+	// The opening bracket of the results array.
+	if _, err := decoder.Token(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for decoder.More() {
+		item := new(serviceutils.ResultItem)
+		if err := decoder.Decode(item); err != nil {
+			return errorutils.CheckError(err)
+		}
+		handleItem(item)
+	}
+	return nil
+}
+
+// Keeps the file if it's one of the largest files.
+func (sr *StorageReport) addFile(file FileUsage, largestFilesCount int) {
+	if len(sr.LargestFiles) == largestFilesCount && (largestFilesCount == 0 || file.Size <= sr.LargestFiles[largestFilesCount-1].Size) {
+		return
+	}
+	index := sort.Search(len(sr.LargestFiles), func(i int) bool {
+		return sr.LargestFiles[i].Size < file.Size
+	})
+	sr.LargestFiles = append(sr.LargestFiles, FileUsage{})
+	copy(sr.LargestFiles[index+1:], sr.LargestFiles[index:])
+	sr.LargestFiles[index] = file
+	if len(sr.LargestFiles) > largestFilesCount {
+		sr.LargestFiles = sr.LargestFiles[:largestFilesCount]
+	}
+}
+
+// Sets the growth of the repositories and folders since the previous report.
+// Repositories and folders which didn't exist in the previous report grew by their entire usage.
+func (sr *StorageReport) calculateGrowth(previous *StorageReport) {
+	previousRepos := make(map[string]*RepositoryUsage)
+	for i := range previous.Repositories {
+		previousRepos[previous.Repositories[i].Repo] = &previous.Repositories[i]
+	}
+	for i := range sr.Repositories {
+		repo := &sr.Repositories[i]
+		previousFolders := make(map[string]Usage)
+		var previousRepoUsage Usage
+		if previousRepo, exists := previousRepos[repo.Repo]; exists {
+			previousRepoUsage = previousRepo.Usage
+			for _, folder := range previousRepo.Folders {
+				previousFolders[folder.Path] = folder.Usage
+			}
+		}
+		repo.Growth = repo.Usage.growthSince(previousRepoUsage)
+		for j := range repo.Folders {
+			repo.Folders[j].Growth = repo.Folders[j].Usage.growthSince(previousFolders[repo.Folders[j].Path])
+		}
+	}
+}
+
+func (u Usage) growthSince(previous Usage) *UsageGrowth {
+	return &UsageGrowth{Files: u.Files - previous.Files, Size: u.Size - previous.Size}
+}
+
+func loadStorageReport(reportPath string) (*StorageReport, error) {
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	report := new(StorageReport)
+	if err = json.Unmarshal(content, report); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the storage report snapshot '%s': %s", reportPath, err.Error())
+	}
+	return report, nil
+}
+
+func (sr *StorageReport) save(reportPath string) error {
+	content, err := json.Marshal(sr)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Info("Saving the storage report snapshot to", reportPath)
+	return errorutils.CheckError(os.WriteFile(reportPath, content, 0600))
+}
+
+func (sr *StorageReport) print(format OutputFormat, previousReport *StorageReport) error {
+	switch format {
+	case Json:
+		content, err := json.Marshal(sr)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(clientutils.IndentJson(content))
+		return nil
+	case Csv:
+		return sr.printCsv()
+	}
+	return sr.printTables(previousReport)
+}
+
+func (sr *StorageReport) printTables(previousReport *StorageReport) error {
+	var repoRows []repositoryRow
+	var folderRows []folderRow
+	for _, repo := range sr.Repositories {
+		filesGrowth, sizeGrowth := repo.Usage.growthToText()
+		repoRows = append(repoRows, repositoryRow{Repo: repo.Repo, Files: strconv.FormatInt(repo.Files, 10), Size: utils.ConvertIntToStorageSizeString(repo.Size), FilesGrowth: filesGrowth, SizeGrowth: sizeGrowth})
+		for _, folder := range repo.Folders {
+			filesGrowth, sizeGrowth = folder.Usage.growthToText()
+			folderRows = append(folderRows, folderRow{Repo: repo.Repo, Folder: folder.Path, Files: strconv.FormatInt(folder.Files, 10), Size: utils.ConvertIntToStorageSizeString(folder.Size), FilesGrowth: filesGrowth, SizeGrowth: sizeGrowth})
+		}
+	}
+	var fileRows []fileRow
+	for _, file := range sr.LargestFiles {
+		fileRows = append(fileRows, fileRow{Path: file.Path, Size: utils.ConvertIntToStorageSizeString(file.Size)})
+	}
+	// The growth columns are printed only when there's a previous report to compare to.
+	printGrowth := previousReport != nil
+	repositoriesTitle := "Repositories"
+	if printGrowth {
+		repositoriesTitle += " (growth since " + previousReport.Created + ")"
+	}
+	if err := coreutils.PrintTable(repoRows, repositoriesTitle, "No repositories were found", printGrowth); err != nil {
+		return err
+	}
+	if err := coreutils.PrintTable(folderRows, "Top-Level Folders", "No folders were found", printGrowth); err != nil {
+		return err
+	}
+	return coreutils.PrintTable(fileRows, "Largest Files", "No files were found", false)
+}
+
+func (u Usage) growthToText() (files, size string) {
+	if u.Growth == nil {
+		return
+	}
+	files = strconv.FormatInt(u.Growth.Files, 10)
+	if u.Growth.Files > 0 {
+		files = "+" + files
+	}
+	switch {
+	case u.Growth.Size > 0:
+		size = "+" + utils.ConvertIntToStorageSizeString(u.Growth.Size)
+	case u.Growth.Size < 0:
+		size = "-" + utils.ConvertIntToStorageSizeString(-u.Growth.Size)
+	default:
+		size = utils.ConvertIntToStorageSizeString(0)
+	}
+	return
+}
+
+// Prints a row for each repository, folder and largest file. The sizes are in bytes.
+func (sr *StorageReport) printCsv() error {
+	var output strings.Builder
+	csvWriter := csv.NewWriter(&output)
+	records := [][]string{{"kind", "repo", "path", "files", "size", "files_growth", "size_growth"}}
+	for _, repo := range sr.Repositories {
+		records = append(records, repo.Usage.toCsvRecord("repo", repo.Repo, ""))
+		for _, folder := range repo.Folders {
+			records = append(records, folder.Usage.toCsvRecord("folder", repo.Repo, folder.Path))
+		}
+	}
+	for _, file := range sr.LargestFiles {
+		repo, filePath, _ := strings.Cut(file.Path, "/")
+		records = append(records, Usage{Files: 1, Size: file.Size}.toCsvRecord("file", repo, filePath))
+	}
+	if err := csvWriter.WriteAll(records); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Output(strings.TrimSuffix(output.String(), "\n"))
+	return nil
+}
+
+func (u Usage) toCsvRecord(kind, repo, itemPath string) []string {
+	record := []string{kind, repo, itemPath, strconv.FormatInt(u.Files, 10), strconv.FormatInt(u.Size, 10), "", ""}
+	if u.Growth != nil {
+		record[5] = strconv.FormatInt(u.Growth.Files, 10)
+		record[6] = strconv.FormatInt(u.Growth.Size, 10)
+	}
+	return record
+}
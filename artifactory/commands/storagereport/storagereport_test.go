@@ -0,0 +1,102 @@
+package storagereport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	serviceutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageReportCommand(t *testing.T) {
+	repoItems := map[string][]serviceutils.ResultItem{
+		"repo-1": {
+			{Path: "a/b", Name: "1.bin", Size: 100},
+			{Path: "a", Name: "2.bin", Size: 50},
+			{Path: ".", Name: "3.bin", Size: 10},
+		},
+		"repo-2": {
+			{Path: "c", Name: "4.bin", Size: 500},
+		},
+	}
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/storageinfo/calculate":
+			w.WriteHeader(http.StatusAccepted)
+		case "/api/storageinfo":
+			storageInfo := serviceutils.StorageInfo{RepositoriesSummaryList: []serviceutils.RepositorySummary{
+				{RepoKey: "repo-1", RepoType: "LOCAL", FilesCount: "3", UsedSpaceInBytes: "160"},
+				{RepoKey: "repo-2", RepoType: "LOCAL", FilesCount: "1", UsedSpace: "500 bytes"},
+				{RepoKey: "TOTAL", RepoType: "NA", FilesCount: "4", UsedSpaceInBytes: "660"},
+			}}
+			content, err := json.Marshal(storageInfo)
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		case "/api/search/aql":
+			query, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var results []serviceutils.ResultItem
+			for repo, items := range repoItems {
+				if strings.Contains(string(query), `"repo":"`+repo+`"`) {
+					results = items
+				}
+			}
+			content, err := json.Marshal(map[string]interface{}{"results": results})
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	storageReportCommand := NewStorageReportCommand().SetServerDetails(serverDetails).SetLargestFilesCount(2).SetFormat(Json).SetSnapshotPath(snapshotPath)
+	assert.NoError(t, storageReportCommand.Run())
+	report := storageReportCommand.Report()
+	expectedRepos := []RepositoryUsage{
+		{Repo: "repo-2", Usage: Usage{Files: 1, Size: 500}, Folders: []FolderUsage{{Path: "c", Usage: Usage{Files: 1, Size: 500}}}},
+		{Repo: "repo-1", Usage: Usage{Files: 3, Size: 160}, Folders: []FolderUsage{{Path: "a", Usage: Usage{Files: 2, Size: 150}}, {Path: ".", Usage: Usage{Files: 1, Size: 10}}}},
+	}
+	assert.Equal(t, expectedRepos, report.Repositories)
+	assert.Equal(t, []FileUsage{{Path: "repo-2/c/4.bin", Size: 500}, {Path: "repo-1/a/b/1.bin", Size: 100}}, report.LargestFiles)
+
+	// The growth is calculated since the saved snapshot.
+	repoItems["repo-1"] = append(repoItems["repo-1"], serviceutils.ResultItem{Path: "d", Name: "5.bin", Size: 40})
+	storageReportCommand = NewStorageReportCommand().SetServerDetails(serverDetails).SetRepos([]string{"repo-1"}).SetFormat(Csv).SetPreviousSnapshotPath(snapshotPath)
+	assert.NoError(t, storageReportCommand.Run())
+	report = storageReportCommand.Report()
+	if assert.Len(t, report.Repositories, 1) {
+		// The repository's usage is taken from the storage info, which wasn't updated.
+		assert.Equal(t, &UsageGrowth{}, report.Repositories[0].Growth)
+		assert.Equal(t, []FolderUsage{
+			{Path: "a", Usage: Usage{Files: 2, Size: 150, Growth: &UsageGrowth{}}},
+			{Path: "d", Usage: Usage{Files: 1, Size: 40, Growth: &UsageGrowth{Files: 1, Size: 40}}},
+			{Path: ".", Usage: Usage{Files: 1, Size: 10, Growth: &UsageGrowth{}}},
+		}, report.Repositories[0].Folders)
+	}
+}
+
+func TestStorageReportAddFile(t *testing.T) {
+	report := new(StorageReport)
+	for i, size := range []int64{5, 1, 9, 3, 7} {
+		report.addFile(FileUsage{Path: string(rune('a' + i)), Size: size}, 3)
+	}
+	assert.Equal(t, []FileUsage{{Path: "c", Size: 9}, {Path: "e", Size: 7}, {Path: "a", Size: 5}}, report.LargestFiles)
+
+	report = new(StorageReport)
+	report.addFile(FileUsage{Path: "a", Size: 1}, 0)
+	assert.Empty(t, report.LargestFiles)
+}
+
+func TestStorageReportCommandNegativeLargestFilesCount(t *testing.T) {
+	storageReportCommand := NewStorageReportCommand().SetLargestFilesCount(-1)
+	assert.ErrorContains(t, storageReportCommand.Run(), "must not be negative")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
 	commandsUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -52,6 +53,8 @@ type TransferFilesCommand struct {
 	stopSignal                chan os.Signal
 	stateManager              *state.TransferStateManager
 	preChecks                 bool
+	// When true, the transferred repositories are printed as a formats.TransferCommandOutput JSON.
+	structuredOutput bool
 }
 
 func NewTransferFilesCommand(sourceServer, targetServer *config.ServerDetails) (*TransferFilesCommand, error) {
@@ -107,6 +110,10 @@ func (tdc *TransferFilesCommand) SetPreChecks(check bool) {
 	tdc.preChecks = check
 }
 
+func (tdc *TransferFilesCommand) SetStructuredOutput(structuredOutput bool) {
+	tdc.structuredOutput = structuredOutput
+}
+
 func (tdc *TransferFilesCommand) Run() (err error) {
 	if tdc.status {
 		return ShowStatus()
@@ -596,6 +603,53 @@ func (tdc *TransferFilesCommand) cleanup(originalErr error, sourceRepos []string
 	if csvErrorsFile != "" {
 		log.Info(fmt.Sprintf("Errors occurred during the transfer. Check the errors summary CSV file in: %s", csvErrorsFile))
 	}
+
+	if tdc.structuredOutput {
+		if e = tdc.printCommandOutput(originalErr, sourceRepos); e != nil {
+			log.Error("Couldn't print the transfer output", e)
+			if err == nil {
+				err = e
+			}
+		}
+	}
+	return
+}
+
+// Prints the transfer state of each of the given source repositories as JSON.
+func (tdc *TransferFilesCommand) printCommandOutput(runErr error, sourceRepos []string) error {
+	items := make([]formats.TransferRepositoryOutputItem, 0, len(sourceRepos))
+	for _, repoKey := range sourceRepos {
+		item, err := getTransferRepositoryOutputItem(repoKey)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	output := commandsUtils.NewTransferCommandOutput(tdc.CommandName(), tdc.targetServerDetails.ServerId, time.Since(tdc.timeStarted), items, runErr)
+	return commandsUtils.PrintCommandOutput(output)
+}
+
+// A repository is transferred successfully if its full transfer phase ended, and none of its files failed to transfer.
+func getTransferRepositoryOutputItem(repoKey string) (item formats.TransferRepositoryOutputItem, err error) {
+	item = formats.TransferRepositoryOutputItem{Repository: repoKey, Status: formats.CommandOutputStatusFailure}
+	transferState, exists, err := state.LoadTransferState(repoKey, false)
+	if err != nil || !exists {
+		return
+	}
+	failedFiles, err := getRetryErrorCount([]string{repoKey})
+	if err != nil {
+		return
+	}
+	item.TransferredFiles = transferState.CurrentRepo.Phase1Info.TransferredUnits
+	item.TransferredBytes = transferState.CurrentRepo.Phase1Info.TransferredSizeBytes
+	item.FailedFiles = int64(failedFiles)
+	switch {
+	case transferState.CurrentRepo.FullTransfer.Ended == "":
+	case item.FailedFiles > 0:
+		item.Status = formats.CommandOutputStatusPartial
+	default:
+		item.Status = formats.CommandOutputStatusSuccess
+	}
 	return
 }
 
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// NewCommandOutputEnvelope creates the envelope shared by all the commands' JSON outputs.
+// commandName - the command name, as returned by commands.Command.CommandName().
+// serverId - the ID of the server the command ran against. May be empty.
+// duration - the command's run duration.
+// success, failure - the number of items the command succeeded and failed to handle.
+// runErr - the command's resulting error.
+func NewCommandOutputEnvelope(commandName, serverId string, duration time.Duration, success, failure int, runErr error) formats.CommandOutputEnvelope {
+	envelope := formats.CommandOutputEnvelope{
+		SchemaVersion: formats.CommandOutputSchemaVersion,
+		Command:       commandName,
+		ServerId:      serverId,
+		DurationMs:    duration.Milliseconds(),
+		Totals:        formats.OutputTotals{Success: success, Failure: failure},
+	}
+	envelope.SetStatus(runErr)
+	return envelope
+}
+
+// NewGenericCommandOutput creates the JSON output of a generic command from its Result.
+// The output items are read from the result's reader, if available.
+func NewGenericCommandOutput(commandName, serverId string, duration time.Duration, result *Result, runErr error) (output *formats.GenericCommandOutput, err error) {
+	output = &formats.GenericCommandOutput{Items: []formats.FileOutputItem{}}
+	if result == nil {
+		output.CommandOutputEnvelope = NewCommandOutputEnvelope(commandName, serverId, duration, 0, 0, runErr)
+		return
+	}
+	output.CommandOutputEnvelope = NewCommandOutputEnvelope(commandName, serverId, duration, result.SuccessCount(), result.FailCount(), runErr)
+	reader := result.Reader()
+	if reader == nil {
+		return
+	}
+	defer reader.Reset()
+	for transferDetails := new(clientutils.FileTransferDetails); reader.NextRecord(transferDetails) == nil; transferDetails = new(clientutils.FileTransferDetails) {
+		output.Items = append(output.Items, formats.FileOutputItem{
+			SourcePath: transferDetails.SourcePath,
+			TargetPath: transferDetails.TargetPath,
+			RtUrl:      transferDetails.RtUrl,
+			Sha256:     transferDetails.Sha256,
+		})
+	}
+	err = errorutils.CheckError(reader.GetError())
+	return
+}
+
+// NewBuildCommandOutput creates the JSON output of a build command.
+// items - the builds the command handled. All of them are counted as failed if the command returned an error.
+func NewBuildCommandOutput(commandName, serverId string, duration time.Duration, items []formats.BuildOutputItem, runErr error) *formats.BuildCommandOutput {
+	if items == nil {
+		items = []formats.BuildOutputItem{}
+	}
+	success, failure := countItemsByError(len(items), runErr)
+	return &formats.BuildCommandOutput{
+		CommandOutputEnvelope: NewCommandOutputEnvelope(commandName, serverId, duration, success, failure, runErr),
+		Items:                 items,
+	}
+}
+
+// NewDistributionCommandOutput creates the JSON output of a release bundle distribution command.
+// items - the release bundles the command handled. All of them are counted as failed if the command returned an error.
+func NewDistributionCommandOutput(commandName, serverId string, duration time.Duration, items []formats.ReleaseBundleOutputItem, runErr error) *formats.DistributionCommandOutput {
+	if items == nil {
+		items = []formats.ReleaseBundleOutputItem{}
+	}
+	success, failure := countItemsByError(len(items), runErr)
+	return &formats.DistributionCommandOutput{
+		CommandOutputEnvelope: NewCommandOutputEnvelope(commandName, serverId, duration, success, failure, runErr),
+		Items:                 items,
+	}
+}
+
+// NewTransferCommandOutput creates the JSON output of a transfer command.
+// items - the repositories the command handled. Repositories with a status other than success are counted as failed.
+func NewTransferCommandOutput(commandName, serverId string, duration time.Duration, items []formats.TransferRepositoryOutputItem, runErr error) *formats.TransferCommandOutput {
+	if items == nil {
+		items = []formats.TransferRepositoryOutputItem{}
+	}
+	success := 0
+	for _, item := range items {
+		if item.Status == formats.CommandOutputStatusSuccess {
+			success++
+		}
+	}
+	return &formats.TransferCommandOutput{
+		CommandOutputEnvelope: NewCommandOutputEnvelope(commandName, serverId, duration, success, len(items)-success, runErr),
+		Items:                 items,
+	}
+}
+
+// PrintCommandOutput prints the given command output as JSON.
+func PrintCommandOutput(output interface{}) error {
+	content, err := formats.CommandOutputToJson(output)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Output(strings.TrimSuffix(string(content), "\n"))
+	return nil
+}
+
+func countItemsByError(itemsCount int, runErr error) (success, failure int) {
+	if runErr != nil {
+		return 0, itemsCount
+	}
+	return itemsCount, 0
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGenericCommandOutput(t *testing.T) {
+	tempDeployableArtifacts, err := createTempDeployableArtifactFile()
+	assert.NoError(t, err)
+	defer testsutils.RemoveAllAndAssert(t, filepath.Dir(tempDeployableArtifacts))
+	artifactsArray := []clientutils.FileTransferDetails{{SourcePath: "a.zip", TargetPath: "repo/a.zip", RtUrl: "http://localhost:8080/artifactory/", Sha256: "abc"}}
+	assert.NoError(t, clientutils.SaveFileTransferDetailsInFile(tempDeployableArtifacts, &artifactsArray))
+	result := new(Result)
+	result.SetSuccessCount(1)
+	result.SetFailCount(2)
+	result.SetReader(content.NewContentReader(tempDeployableArtifacts, "files"))
+	defer func() {
+		assert.NoError(t, result.Reader().Close())
+	}()
+
+	output, err := NewGenericCommandOutput("rt_upload", "my-server", 1500*time.Millisecond, result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, formats.CommandOutputSchemaVersion, output.SchemaVersion)
+	assert.Equal(t, "rt_upload", output.Command)
+	assert.Equal(t, "my-server", output.ServerId)
+	assert.Equal(t, int64(1500), output.DurationMs)
+	assert.Equal(t, formats.CommandOutputStatusPartial, output.Status)
+	assert.Equal(t, formats.OutputTotals{Success: 1, Failure: 2}, output.Totals)
+	assert.Equal(t, []formats.FileOutputItem{{SourcePath: "a.zip", TargetPath: "repo/a.zip", RtUrl: "http://localhost:8080/artifactory/", Sha256: "abc"}}, output.Items)
+
+	output, err = NewGenericCommandOutput("rt_delete", "", 0, nil, errors.New("failed"))
+	assert.NoError(t, err)
+	assert.Equal(t, formats.CommandOutputStatusFailure, output.Status)
+	assert.Empty(t, output.Items)
+}
+
+func TestCommandOutputEnvelopeStatus(t *testing.T) {
+	testCases := []struct {
+		success        int
+		failure        int
+		runErr         error
+		expectedStatus string
+	}{
+		{3, 0, nil, formats.CommandOutputStatusSuccess},
+		{0, 0, nil, formats.CommandOutputStatusSuccess},
+		{0, 2, nil, formats.CommandOutputStatusFailure},
+		{2, 1, nil, formats.CommandOutputStatusPartial},
+		{2, 1, errors.New("failed"), formats.CommandOutputStatusPartial},
+		{2, 0, errors.New("failed"), formats.CommandOutputStatusFailure},
+	}
+	for _, testCase := range testCases {
+		envelope := NewCommandOutputEnvelope("rt_upload", "", 0, testCase.success, testCase.failure, testCase.runErr)
+		assert.Equal(t, testCase.expectedStatus, envelope.Status, testCase)
+	}
+}
+
+func TestNewBuildCommandOutput(t *testing.T) {
+	items := []formats.BuildOutputItem{{BuildName: "build", BuildNumber: "1", BuildInfoUiUrl: "http://localhost:8082/ui/builds/build/1"}}
+	output := NewBuildCommandOutput("rt_build_publish", "my-server", time.Second, items, nil)
+	assert.Equal(t, formats.CommandOutputStatusSuccess, output.Status)
+	assert.Equal(t, formats.OutputTotals{Success: 1}, output.Totals)
+	assert.Equal(t, items, output.Items)
+
+	output = NewBuildCommandOutput("rt_build_publish", "my-server", time.Second, items, errors.New("failed"))
+	assert.Equal(t, formats.CommandOutputStatusFailure, output.Status)
+	assert.Equal(t, formats.OutputTotals{Failure: 1}, output.Totals)
+}
+
+func TestNewDistributionCommandOutput(t *testing.T) {
+	items := []formats.ReleaseBundleOutputItem{{Name: "bundle", Version: "1.0.0"}}
+	output := NewDistributionCommandOutput("rt_distribute_bundle", "", 0, items, nil)
+	assert.Equal(t, formats.CommandOutputStatusSuccess, output.Status)
+	assert.Equal(t, formats.OutputTotals{Success: 1}, output.Totals)
+	assert.Equal(t, items, output.Items)
+
+	output = NewDistributionCommandOutput("rt_distribute_bundle", "", 0, nil, errors.New("failed"))
+	assert.Equal(t, formats.CommandOutputStatusFailure, output.Status)
+	assert.NotNil(t, output.Items)
+}
+
+func TestNewTransferCommandOutput(t *testing.T) {
+	items := []formats.TransferRepositoryOutputItem{
+		{Repository: "repo1", Status: formats.CommandOutputStatusSuccess, TransferredFiles: 2, TransferredBytes: 20},
+		{Repository: "repo2", Status: formats.CommandOutputStatusPartial, TransferredFiles: 1, TransferredBytes: 10, FailedFiles: 1},
+	}
+	output := NewTransferCommandOutput("rt_transfer_files", "target", time.Minute, items, nil)
+	assert.Equal(t, formats.CommandOutputStatusPartial, output.Status)
+	assert.Equal(t, formats.OutputTotals{Success: 1, Failure: 1}, output.Totals)
+	assert.Equal(t, int64(60000), output.DurationMs)
+	assert.Equal(t, items, output.Items)
+}
@@ -21,7 +21,9 @@ const (
 type Result struct {
 	successCount int
 	failCount    int
-	reader       *content.ContentReader
+	// The number of bytes which weren't transferred, since the files already existed in Artifactory.
+	bytesSaved int64
+	reader     *content.ContentReader
 }
 
 func (r *Result) SuccessCount() int {
@@ -32,6 +34,10 @@ func (r *Result) FailCount() int {
 	return r.failCount
 }
 
+func (r *Result) BytesSaved() int64 {
+	return r.bytesSaved
+}
+
 func (r *Result) Reader() *content.ContentReader {
 	return r.reader
 }
@@ -44,6 +50,10 @@ func (r *Result) SetFailCount(failCount int) {
 	r.failCount = failCount
 }
 
+func (r *Result) SetBytesSaved(bytesSaved int64) {
+	r.bytesSaved = bytesSaved
+}
+
 func (r *Result) SetReader(reader *content.ContentReader) {
 	r.reader = reader
 }
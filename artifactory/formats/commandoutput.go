@@ -0,0 +1,119 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Structs in this file should NOT be changed!
+// The structs are used as a versioned API for the commands' JSON output, thus changing their structure or the 'json' annotation will break the API.
+// Any breaking change requires a new CommandOutputSchemaVersion.
+
+const CommandOutputSchemaVersion = "1"
+
+const (
+	CommandOutputStatusSuccess = "success"
+	CommandOutputStatusFailure = "failure"
+	// Some of the items were handled successfully, and some failed.
+	CommandOutputStatusPartial = "partial"
+)
+
+// The envelope shared by all the commands' outputs.
+type CommandOutputEnvelope struct {
+	SchemaVersion string       `json:"schemaVersion"`
+	Command       string       `json:"command"`
+	ServerId      string       `json:"serverId,omitempty"`
+	DurationMs    int64        `json:"durationMs"`
+	Status        string       `json:"status"`
+	Totals        OutputTotals `json:"totals"`
+}
+
+type OutputTotals struct {
+	Success int `json:"success"`
+	Failure int `json:"failure"`
+}
+
+// Output of the generic commands - upload, download, copy, move, delete, set-props and delete-props.
+type GenericCommandOutput struct {
+	CommandOutputEnvelope
+	Items []FileOutputItem `json:"items"`
+}
+
+type FileOutputItem struct {
+	SourcePath string `json:"sourcePath,omitempty"`
+	TargetPath string `json:"targetPath,omitempty"`
+	RtUrl      string `json:"rtUrl,omitempty"`
+	Sha256     string `json:"sha256,omitempty"`
+}
+
+// Output of the build commands - build-publish, build-promote, build-discard, build-add-dependencies, etc.
+type BuildCommandOutput struct {
+	CommandOutputEnvelope
+	Items []BuildOutputItem `json:"items"`
+}
+
+type BuildOutputItem struct {
+	BuildName      string `json:"buildName"`
+	BuildNumber    string `json:"buildNumber,omitempty"`
+	Project        string `json:"project,omitempty"`
+	BuildInfoUiUrl string `json:"buildInfoUiUrl,omitempty"`
+}
+
+// Output of the release bundles distribution commands.
+type DistributionCommandOutput struct {
+	CommandOutputEnvelope
+	Items []ReleaseBundleOutputItem `json:"items"`
+}
+
+type ReleaseBundleOutputItem struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	DistributionId  string `json:"distributionId,omitempty"`
+	SiteName        string `json:"siteName,omitempty"`
+	DistributionMsg string `json:"distributionMessage,omitempty"`
+}
+
+// Output of the transfer-files and transfer-config commands.
+type TransferCommandOutput struct {
+	CommandOutputEnvelope
+	Items []TransferRepositoryOutputItem `json:"items"`
+}
+
+type TransferRepositoryOutputItem struct {
+	Repository       string `json:"repository"`
+	Status           string `json:"status"`
+	TransferredFiles int64  `json:"transferredFiles"`
+	TransferredBytes int64  `json:"transferredBytes"`
+	FailedFiles      int64  `json:"failedFiles"`
+}
+
+// Sets the status by the command's resulting error only.
+func (env *CommandOutputEnvelope) SetStatusByError(err error) {
+	if err != nil {
+		env.Status = CommandOutputStatusFailure
+		return
+	}
+	env.Status = CommandOutputStatusSuccess
+}
+
+// Sets the status by the command's resulting error and the envelope's totals.
+func (env *CommandOutputEnvelope) SetStatus(err error) {
+	switch {
+	case env.Totals.Failure > 0 && env.Totals.Success > 0:
+		env.Status = CommandOutputStatusPartial
+	case err != nil || env.Totals.Failure > 0:
+		env.Status = CommandOutputStatusFailure
+	default:
+		env.Status = CommandOutputStatusSuccess
+	}
+}
+
+// Marshals the given command output. This function is similar to json.Marshal with EscapeHTML false.
+func CommandOutputToJson(output interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(output)
+	return buffer.Bytes(), err
+}
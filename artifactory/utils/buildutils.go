@@ -15,6 +15,7 @@ import (
 
 	"github.com/jfrog/build-info-go/build"
 	buildInfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	artClientUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
@@ -347,11 +348,25 @@ func (bc *BuildConfiguration) GetBuildName() (string, error) {
 		return bc.buildName, nil
 	}
 	// Resolve from config file in '.jfrog' folder.
-	var err error
-	if bc.buildName, err = bc.getBuildNameFromConfigFile(); bc.buildName != "" {
+	buildName, err := bc.getBuildNameFromConfigFile()
+	if err != nil {
+		return "", err
+	}
+	if buildName != "" {
+		bc.buildName = buildName
 		bc.loadedFromConfigFile = true
+		return bc.buildName, nil
+	}
+	// Resolve from the active context.
+	context, err := config.GetActiveContext()
+	if err != nil {
+		log.Warn("Couldn't resolve the build name from the active context: " + err.Error())
+		return "", nil
 	}
-	return bc.buildName, err
+	if context != nil {
+		bc.buildName = context.BuildName
+	}
+	return bc.buildName, nil
 }
 
 func (bc *BuildConfiguration) getBuildNameFromConfigFile() (string, error) {
@@ -394,7 +409,18 @@ func (bc *BuildConfiguration) GetProject() string {
 		return bc.project
 	}
 	// Resolve from env var.
-	bc.project = os.Getenv(coreutils.Project)
+	if bc.project = os.Getenv(coreutils.Project); bc.project != "" {
+		return bc.project
+	}
+	// Resolve from the active context.
+	context, err := config.GetActiveContext()
+	if err != nil {
+		log.Warn("Couldn't resolve the project from the active context: " + err.Error())
+		return ""
+	}
+	if context != nil {
+		bc.project = context.Project
+	}
 	return bc.project
 }
 
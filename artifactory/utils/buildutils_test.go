@@ -10,6 +10,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
 
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	artclientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -69,6 +70,41 @@ func TestGetBuildName(t *testing.T) {
 	assert.Equal(t, buildNameFile, actualBuildName)
 }
 
+func TestGetBuildNameFromContext(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+	assert.NoError(t, config.SaveContext(&config.ContextDetails{Name: "ctx", BuildName: "contextBuildName"}))
+	assert.NoError(t, config.UseContext("ctx"))
+
+	// Build name from the active context, when no build config file exists.
+	tmpDir, createTempDirCallback := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer createTempDirCallback()
+	wd, err := os.Getwd()
+	assert.NoError(t, err, "Failed to get current dir")
+	chdirCallBack := testsutils.ChangeDirWithCallback(t, wd, tmpDir)
+	defer chdirCallBack()
+	actualBuildName, err := NewBuildConfiguration("", "", "", "").GetBuildName()
+	assert.NoError(t, err)
+	assert.Equal(t, "contextBuildName", actualBuildName)
+
+	// The build config file takes precedence over the active context.
+	assert.NoError(t, fileutils.CopyFile(filepath.Join(tmpDir, ".jfrog", "projects"), filepath.Join(wd, "testdata", "build.yaml")))
+	buildConfig := NewBuildConfiguration("", "", "", "")
+	actualBuildName, err = buildConfig.GetBuildName()
+	assert.NoError(t, err)
+	assert.Equal(t, buildNameFile, actualBuildName)
+	assert.True(t, buildConfig.IsLoadedFromConfigFile())
+
+	// A context which doesn't exist is ignored.
+	assert.NoError(t, os.RemoveAll(filepath.Join(tmpDir, ".jfrog")))
+	testsutils.SetEnvAndAssert(t, coreutils.Context, "not-exist")
+	defer testsutils.UnSetEnvAndAssert(t, coreutils.Context)
+	actualBuildName, err = NewBuildConfiguration("", "", "", "").GetBuildName()
+	assert.NoError(t, err)
+	assert.Empty(t, actualBuildName)
+}
+
 func TestGetEmptyBuildNameOnUnixAccessDenied(t *testing.T) {
 	if coreutils.IsWindows() {
 		t.Skip("Skipping TestGetEmptyBuildNameOnUnixAccessDenied test on windows...")
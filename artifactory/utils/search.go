@@ -23,6 +23,13 @@ type SearchResult struct {
 	Props    map[string][]string `json:"props,omitempty"`
 }
 
+// The search results are stored with additional fields, which are printed only when selected or in the output formats which support them.
+// The default json output includes only the fields of SearchResult.
+type extendedSearchResult struct {
+	SearchResult
+	Sha256 string `json:"sha256,omitempty"`
+}
+
 func PrintSearchResults(reader *content.ContentReader) error {
 	length, err := reader.Length()
 	if length == 0 {
@@ -67,7 +74,7 @@ func AqlResultToSearchResult(readers []*content.ContentReader) (*content.Content
 			if err != nil {
 				return nil, err
 			}
-			tempResult := new(SearchResult)
+			tempResult := new(extendedSearchResult)
 			tempResult.Path = searchResult.Repo + "/"
 			if searchResult.Path != "." {
 				tempResult.Path += searchResult.Path + "/"
@@ -81,6 +88,7 @@ func AqlResultToSearchResult(readers []*content.ContentReader) (*content.Content
 			tempResult.Modified = searchResult.Modified
 			tempResult.Sha1 = searchResult.Actual_Sha1
 			tempResult.Md5 = searchResult.Actual_Md5
+			tempResult.Sha256 = searchResult.Sha256
 			tempResult.Props = make(map[string][]string, len(searchResult.Properties))
 			for _, prop := range searchResult.Properties {
 				tempResult.Props[prop.Key] = append(tempResult.Props[prop.Key], prop.Value)
@@ -3,10 +3,12 @@ package utils
 import (
 	"bytes"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
 
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/content"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +38,127 @@ func TestPrintSearchResults(t *testing.T) {
 	assert.Equal(t, 0, compareResult)
 }
 
+func TestPrintSearchResultsWithFormat(t *testing.T) {
+	testCases := []struct {
+		name           string
+		format         SearchOutputFormat
+		fields         []string
+		expectedOutput []string
+	}{
+		{"jsonWithFields", SearchJson, []string{"size", "path"}, []string{
+			`[`,
+			`  {`,
+			`    "size": 11,`,
+			`    "path": "jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in"`,
+			`  },`,
+		}},
+		{"ndjson", SearchNdjson, nil, []string{
+			`{"path":"jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in","type":"file","size":11,"created":"2020-07-20T21:39:38.374+03:00","modified":"2020-07-20T21:39:38.332+03:00","sha1":"a4f912be11e7d1d346e34c300e6d4b90e136896e","md5":"82b6d565393a3fd1cc4778b1d53c0664","props":{"c":["3"]}}`,
+		}},
+		{"ndjsonWithFields", SearchNdjson, []string{"path", "props.a", "props"}, []string{
+			`{"path":"jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in","props.a":null,"props":{"c":["3"]}}`,
+		}},
+		{"csv", SearchCsv, nil, []string{
+			`path,type,size,created,modified,sha256`,
+			`jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in,file,11,2020-07-20T21:39:38.374+03:00,2020-07-20T21:39:38.332+03:00,`,
+		}},
+		{"csvWithFields", SearchCsv, []string{"path", "props"}, []string{
+			`path,props`,
+			`jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in,c=3`,
+			`jfrog-cli-tests-repo1-1595270324/a/b/c/c3.in,c=3`,
+			`jfrog-cli-tests-repo1-1595270324/a/b/b2.in,b=1;c=3`,
+		}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reader, buffer, cleanUp := prepareSearchResultsPrinting(t)
+			defer cleanUp()
+			assert.NoError(t, PrintSearchResultsWithFormat(reader, testCase.format, testCase.fields))
+			outputLines := strings.Split(buffer.String(), "\n")
+			if assert.GreaterOrEqual(t, len(outputLines), len(testCase.expectedOutput)) {
+				assert.Equal(t, testCase.expectedOutput, outputLines[:len(testCase.expectedOutput)])
+			}
+		})
+	}
+}
+
+func TestPrintSearchResultsSha256(t *testing.T) {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	assert.NoError(t, err)
+	writer.Write(utils.ResultItem{Repo: "repo", Path: "a", Name: "b.zip", Type: "file", Sha256: "1234"})
+	assert.NoError(t, writer.Close())
+	aqlReader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer func() {
+		assert.NoError(t, aqlReader.Close())
+	}()
+	reader, err := AqlResultToSearchResult([]*content.ContentReader{aqlReader})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, reader.Close())
+	}()
+
+	_, buffer, cleanUp := prepareSearchResultsPrinting(t)
+	defer cleanUp()
+	// The default json output doesn't include the SHA256 checksum.
+	assert.NoError(t, PrintSearchResultsWithFormat(reader, SearchJson, nil))
+	assert.NotContains(t, buffer.String(), "sha256")
+
+	buffer.Reset()
+	assert.NoError(t, PrintSearchResultsWithFormat(reader, SearchJson, []string{"path", "sha256"}))
+	assert.Contains(t, buffer.String(), `"sha256": "1234"`)
+
+	buffer.Reset()
+	assert.NoError(t, PrintSearchResultsWithFormat(reader, SearchNdjson, nil))
+	assert.Equal(t, `{"path":"repo/a/b.zip","type":"file","sha256":"1234"}`+"\n", buffer.String())
+}
+
+func TestParseSearchFields(t *testing.T) {
+	fields, err := ParseSearchFields(" path, size ,props.build.name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"path", "size", "props.build.name"}, fields)
+
+	fields, err = ParseSearchFields("")
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+
+	for _, invalidFields := range []string{"path,name", "props.", "path,"} {
+		_, err = ParseSearchFields(invalidFields)
+		assert.Error(t, err, invalidFields)
+	}
+}
+
+func TestGetSearchOutputFormat(t *testing.T) {
+	format, err := GetSearchOutputFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, SearchJson, format)
+
+	format, err = GetSearchOutputFormat("csv")
+	assert.NoError(t, err)
+	assert.Equal(t, SearchCsv, format)
+
+	_, err = GetSearchOutputFormat("xml")
+	assert.Error(t, err)
+}
+
+// Returns a reader of the test search results, and a buffer the log output is redirected to.
+func prepareSearchResultsPrinting(t *testing.T) (reader *content.ContentReader, buffer *bytes.Buffer, cleanUp func()) {
+	testdataPath, err := GetTestDataPath()
+	assert.NoError(t, err)
+	reader = content.NewContentReader(filepath.Join(testdataPath, "search_results.json"), content.DefaultKey)
+
+	previousLog := log.Logger
+	newLog := log.NewLogger(corelog.GetCliLogLevel(), nil)
+	// Set new logger with output redirection to buffer.
+	buffer = &bytes.Buffer{}
+	newLog.SetOutputWriter(buffer)
+	log.SetLogger(newLog)
+	cleanUp = func() {
+		// Restore previous logger.
+		log.SetLogger(previousLog)
+	}
+	return
+}
+
 const expectedLogOutput = `[
   {
     "path": "jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in",
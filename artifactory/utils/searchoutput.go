@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type SearchOutputFormat string
+
+const (
+	// SearchOutputFormat values
+	SearchJson   SearchOutputFormat = "json"
+	SearchNdjson SearchOutputFormat = "ndjson"
+	SearchCsv    SearchOutputFormat = "csv"
+	SearchTable  SearchOutputFormat = "table"
+)
+
+var SearchOutputFormats = []string{string(SearchJson), string(SearchNdjson), string(SearchCsv), string(SearchTable)}
+
+// The fields of the search results which can be selected.
+// In addition, a single property can be selected using the 'props.<key>' field.
+var SearchResultFields = []string{"path", "type", "size", "created", "modified", "sha1", "md5", "sha256", "props"}
+
+const searchResultPropPrefix = "props."
+
+// The fields printed in the csv and table formats, when no fields are selected.
+var defaultTabularSearchFields = []string{"path", "type", "size", "created", "modified", "sha256"}
+
+func GetSearchOutputFormat(format string) (SearchOutputFormat, error) {
+	switch SearchOutputFormat(format) {
+	case "":
+		return SearchJson, nil
+	case SearchJson, SearchNdjson, SearchCsv, SearchTable:
+		return SearchOutputFormat(format), nil
+	}
+	return "", errorutils.CheckErrorf("only the following output formats are supported: %s", coreutils.ListToText(SearchOutputFormats))
+}
+
+// Parses a comma-separated list of search result fields, such as 'path,size,props.build.name'.
+func ParseSearchFields(fields string) ([]string, error) {
+	if strings.TrimSpace(fields) == "" {
+		return nil, nil
+	}
+	var parsedFields []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if !isSearchResultField(field) {
+			return nil, errorutils.CheckErrorf("the '%s' field is not supported. The supported fields are: %s and %s<key>", field, coreutils.ListToText(SearchResultFields), searchResultPropPrefix)
+		}
+		parsedFields = append(parsedFields, field)
+	}
+	return parsedFields, nil
+}
+
+func isSearchResultField(field string) bool {
+	if strings.HasPrefix(field, searchResultPropPrefix) {
+		return len(field) > len(searchResultPropPrefix)
+	}
+	for _, supportedField := range SearchResultFields {
+		if field == supportedField {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintSearchResultsWithFormat prints the search results in the given format, including only the selected fields.
+// If no fields are selected, the json and ndjson formats include all the fields.
+// The results are read from the reader one at a time, except for the table format, which requires all the rows to be rendered together.
+func PrintSearchResultsWithFormat(reader *content.ContentReader, format SearchOutputFormat, fields []string) error {
+	switch format {
+	case SearchNdjson:
+		return printSearchResultsAsNdjson(reader, fields)
+	case SearchCsv:
+		return printSearchResultsAsCsv(reader, getTabularSearchFields(fields))
+	case SearchTable:
+		return printSearchResultsAsTable(reader, getTabularSearchFields(fields))
+	}
+	if len(fields) == 0 {
+		return PrintSearchResults(reader)
+	}
+	return printSelectedFieldsAsJson(reader, fields)
+}
+
+func getTabularSearchFields(fields []string) []string {
+	if len(fields) == 0 {
+		return defaultTabularSearchFields
+	}
+	return fields
+}
+
+func printSelectedFieldsAsJson(reader *content.ContentReader, fields []string) error {
+	log.Output("[")
+	// Each result is printed only once the next one is read, since the last result is printed without a separator.
+	var previous []byte
+	for searchResult := new(extendedSearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(extendedSearchResult) {
+		if previous != nil {
+			log.Output("  " + clientutils.IndentJsonArray(previous) + ",")
+		}
+		var err error
+		if previous, err = marshalSelectedFields(searchResult, fields); err != nil {
+			return err
+		}
+	}
+	if previous != nil {
+		log.Output("  " + clientutils.IndentJsonArray(previous))
+	}
+	log.Output("]")
+	reader.Reset()
+	return reader.GetError()
+}
+
+func printSearchResultsAsNdjson(reader *content.ContentReader, fields []string) error {
+	for searchResult := new(extendedSearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(extendedSearchResult) {
+		var data []byte
+		var err error
+		if len(fields) == 0 {
+			data, err = json.Marshal(searchResult)
+			err = errorutils.CheckError(err)
+		} else {
+			data, err = marshalSelectedFields(searchResult, fields)
+		}
+		if err != nil {
+			return err
+		}
+		log.Output(string(data))
+	}
+	reader.Reset()
+	return reader.GetError()
+}
+
+func printSearchResultsAsCsv(reader *content.ContentReader, fields []string) error {
+	if err := printCsvRecord(fields); err != nil {
+		return err
+	}
+	for searchResult := new(extendedSearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(extendedSearchResult) {
+		if err := printCsvRecord(getSelectedFieldsText(searchResult, fields)); err != nil {
+			return err
+		}
+	}
+	reader.Reset()
+	return reader.GetError()
+}
+
+func printCsvRecord(record []string) error {
+	var line strings.Builder
+	csvWriter := csv.NewWriter(&line)
+	if err := csvWriter.Write(record); err != nil {
+		return errorutils.CheckError(err)
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Output(strings.TrimSuffix(line.String(), "\n"))
+	return nil
+}
+
+// The table's columns are the selected fields, so its row type is created at runtime, with a string field per column.
+func printSearchResultsAsTable(reader *content.ContentReader, fields []string) error {
+	var structFields []reflect.StructField
+	for i, field := range fields {
+		structFields = append(structFields, reflect.StructField{
+			Name: "Field" + strconv.Itoa(i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(`col-name:"` + strings.ReplaceAll(field, `"`, "") + `"`),
+		})
+	}
+	rowType := reflect.StructOf(structFields)
+	rows := reflect.MakeSlice(reflect.SliceOf(rowType), 0, 0)
+	for searchResult := new(extendedSearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(extendedSearchResult) {
+		row := reflect.New(rowType).Elem()
+		for i, value := range getSelectedFieldsText(searchResult, fields) {
+			row.Field(i).SetString(value)
+		}
+		rows = reflect.Append(rows, row)
+	}
+	reader.Reset()
+	if err := reader.GetError(); err != nil {
+		return err
+	}
+	return coreutils.PrintTable(rows.Interface(), "", "No artifacts were found", false)
+}
+
+// Returns the selected fields as a json object, with the fields in the selected order.
+func marshalSelectedFields(searchResult *extendedSearchResult, fields []string) ([]byte, error) {
+	var object strings.Builder
+	object.WriteString("{")
+	for i, field := range fields {
+		key, err := json.Marshal(field)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		value, err := json.Marshal(getSearchResultField(searchResult, field))
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		if i > 0 {
+			object.WriteString(",")
+		}
+		object.Write(key)
+		object.WriteString(":")
+		object.Write(value)
+	}
+	object.WriteString("}")
+	return []byte(object.String()), nil
+}
+
+func getSelectedFieldsText(searchResult *extendedSearchResult, fields []string) []string {
+	var values []string
+	for _, field := range fields {
+		values = append(values, searchResultFieldToText(getSearchResultField(searchResult, field)))
+	}
+	return values
+}
+
+func getSearchResultField(searchResult *extendedSearchResult, field string) interface{} {
+	switch field {
+	case "path":
+		return searchResult.Path
+	case "type":
+		return searchResult.Type
+	case "size":
+		return searchResult.Size
+	case "created":
+		return searchResult.Created
+	case "modified":
+		return searchResult.Modified
+	case "sha1":
+		return searchResult.Sha1
+	case "md5":
+		return searchResult.Md5
+	case "sha256":
+		return searchResult.Sha256
+	case "props":
+		return searchResult.Props
+	}
+	return searchResult.Props[strings.TrimPrefix(field, searchResultPropPrefix)]
+}
+
+// Properties are printed in the 'key1=value1,value2;key2=value3' format.
+func searchResultFieldToText(value interface{}) string {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case int64:
+		return strconv.FormatInt(typedValue, 10)
+	case []string:
+		return strings.Join(typedValue, ",")
+	case map[string][]string:
+		var props []string
+		for key, values := range typedValue {
+			props = append(props, key+"="+strings.Join(values, ","))
+		}
+		sort.Strings(props)
+		return strings.Join(props, ";")
+	}
+	return ""
+}
@@ -11,6 +11,7 @@ import (
 	clientUtils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	"strconv"
 	"strings"
 	"time"
@@ -92,6 +93,39 @@ func (sim *StorageInfoManager) GetRepoSummary(repoKey string) (*utils.Repository
 	return retVal, err
 }
 
+// Get the summaries of all the repositories from the storage info, without the summary of their total.
+// This method must be called after CalculateStorageInfo. It waits only until the storage info includes at least one repository,
+// so repositories whose summaries weren't calculated yet might be missing, and the rest might not be up to date.
+// If no repository is included before the timeout, a warning is logged and an empty list is returned, since Artifactory may have no repositories at all.
+func (sim *StorageInfoManager) GetReposSummaries() ([]utils.RepositorySummary, error) {
+	var retVal []utils.RepositorySummary
+	pollingExecutor := &httputils.PollingExecutor{
+		Timeout:         getRepoSummaryPollingTimeout,
+		PollingInterval: getRepoSummaryPollingInterval,
+		MsgPrefix:       "Waiting for storage info calculation completion",
+		PollingAction: func() (shouldStop bool, responseBody []byte, err error) {
+			storageInfo, err := sim.GetStorageInfo()
+			if err != nil {
+				return true, []byte{}, err
+			}
+			retVal = nil
+			for _, repoSummary := range storageInfo.RepositoriesSummaryList {
+				// The summary list ends with the total of all the repositories, whose type is 'NA'.
+				if repoSummary.RepoType != "NA" {
+					retVal = append(retVal, repoSummary)
+				}
+			}
+			return len(retVal) > 0, []byte{}, nil
+		},
+	}
+	_, err := pollingExecutor.Execute()
+	if errors.As(err, &clientUtils.RetryExecutorTimeoutError{}) {
+		log.Warn("The storage info didn't include any repository after waiting for", getRepoSummaryPollingTimeout.String()+". Either Artifactory has no repositories, or their storage info wasn't calculated yet.")
+		return retVal, nil
+	}
+	return retVal, err
+}
+
 // GetReposTotalSizeAndFiles gets the total size (bytes) and files of all passed repositories.
 // This method must be called after CalculateStorageInfo.
 // The result of this function might not be accurate!
@@ -133,6 +133,43 @@ func TestGetReposTotalSize(t *testing.T) {
 	assert.EqualError(t, err, storageInfoRepoMissingError)
 }
 
+func TestGetReposSummaries(t *testing.T) {
+	getRepoSummaryPollingInterval = 10 * time.Millisecond
+	getRepoSummaryPollingTimeout = 30 * time.Millisecond
+
+	repositoriesSummaryList := []clientUtils.RepositorySummary{
+		{RepoKey: "repo-1", RepoType: "LOCAL", UsedSpaceInBytes: "12345", FilesCount: "3"},
+		{RepoKey: "TOTAL", RepoType: "NA", UsedSpaceInBytes: "12345", FilesCount: "3"},
+	}
+	// Prepare mock server.
+	firstRequest := true
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// In order to test the polling, the first response includes only the total, as if the storage info wasn't calculated yet.
+		if firstRequest {
+			firstRequest = false
+			getStorageInfoResponse(t, w, r, repositoriesSummaryList[1:])
+		} else {
+			getStorageInfoResponse(t, w, r, repositoriesSummaryList)
+		}
+	}))
+	defer testServer.Close()
+
+	storageInfoManager, err := NewStorageInfoManager(context.Background(), &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"})
+	assert.NoError(t, err)
+	repoSummaries, err := storageInfoManager.GetReposSummaries()
+	assert.NoError(t, err)
+	assert.Equal(t, repositoriesSummaryList[:1], repoSummaries)
+}
+
+func TestGetReposSummariesNoRepositories(t *testing.T) {
+	// Only the total is included, so the polling times out.
+	testServer, storageInfoManager := mockGetStorageInfoAndInitManager(t, []clientUtils.RepositorySummary{{RepoKey: "TOTAL", RepoType: "NA", UsedSpaceInBytes: "0", FilesCount: "0"}})
+	defer testServer.Close()
+	repoSummaries, err := storageInfoManager.GetReposSummaries()
+	assert.NoError(t, err)
+	assert.Empty(t, repoSummaries)
+}
+
 func mockGetStorageInfoAndInitManager(t *testing.T, repositoriesSummaryList []clientUtils.RepositorySummary) (*httptest.Server, *StorageInfoManager) {
 	getRepoSummaryPollingInterval = 10 * time.Millisecond
 	getRepoSummaryPollingTimeout = 30 * time.Millisecond
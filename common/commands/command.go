@@ -19,6 +19,14 @@ type Command interface {
 }
 
 func Exec(command Command) error {
+	registeredHooks := getHooks()
+	var hookContext *HookContext
+	if len(registeredHooks) > 0 {
+		hookContext = newHookContext(command)
+		if err := runPreHooks(registeredHooks, hookContext); err != nil {
+			return err
+		}
+	}
 	channel := make(chan bool)
 	// Triggers the report usage.
 	go reportUsage(command, channel)
@@ -26,6 +34,12 @@ func Exec(command Command) error {
 	err := command.Run()
 	// Waits for the signal from the report usage to be done.
 	<-channel
+	if hookContext != nil {
+		hookContext.Err = err
+		if hookErr := runPostHooks(registeredHooks, hookContext); err == nil {
+			err = hookErr
+		}
+	}
 	return err
 }
 
@@ -40,6 +40,7 @@ const (
 	AccessToken AuthenticationMethod = "Access Token"
 	BasicAuth   AuthenticationMethod = "Username and Password / API Key"
 	MTLS        AuthenticationMethod = "Mutual TLS"
+	OIDC        AuthenticationMethod = "OIDC Token Exchange"
 )
 
 // Internal golang locking for the same process.
@@ -92,11 +93,29 @@ func (cc *ConfigCommand) SetDetails(details *config.ServerDetails) *ConfigComman
 }
 
 func (cc *ConfigCommand) Run() (err error) {
-	log.Debug("Locking config file to run config " + cc.cmdType + " command.")
+	return runWithConfigLock(string(cc.cmdType), func() error {
+		switch cc.cmdType {
+		case AddOrEdit:
+			return cc.config()
+		case Delete:
+			return cc.delete()
+		case Use:
+			return cc.use()
+		case Clear:
+			return cc.clear()
+		default:
+			return fmt.Errorf("Not supported config command type: " + string(cc.cmdType))
+		}
+	})
+}
+
+// Runs the provided action while the config file is locked, both for other threads and for other processes.
+func runWithConfigLock(actionName string, action func() error) (err error) {
+	log.Debug("Locking config file to run config " + actionName + " command.")
 	mutex.Lock()
 	defer func() {
 		mutex.Unlock()
-		log.Debug("Config " + cc.cmdType + " command completed successfully. config file is released.")
+		log.Debug("Config " + actionName + " command completed successfully. config file is released.")
 	}()
 
 	lockDirPath, err := coreutils.GetJfrogConfigLockDir()
@@ -114,20 +133,7 @@ func (cc *ConfigCommand) Run() (err error) {
 	if err != nil {
 		return
 	}
-
-	switch cc.cmdType {
-	case AddOrEdit:
-		err = cc.config()
-	case Delete:
-		err = cc.delete()
-	case Use:
-		err = cc.use()
-	case Clear:
-		err = cc.clear()
-	default:
-		err = fmt.Errorf("Not supported config command type: " + string(cc.cmdType))
-	}
-	return
+	return action()
 }
 
 func (cc *ConfigCommand) ServerDetails() (*config.ServerDetails, error) {
@@ -319,8 +325,8 @@ func (cc *ConfigCommand) getConfigurationFromUser() (err error) {
 		if err = cc.promptUrls(&disallowUsingSavedPassword); err != nil {
 			return
 		}
-		// Password/Access-Token/MTLS Certificate
-		if cc.details.Password == "" && cc.details.AccessToken == "" {
+		// Password/Access-Token/MTLS Certificate/OIDC
+		if cc.details.Password == "" && cc.details.AccessToken == "" && cc.details.OidcProviderName == "" {
 			var authMethod AuthenticationMethod
 			authMethod, err = promptAuthMethods()
 			if err != nil {
@@ -352,6 +358,8 @@ func (cc *ConfigCommand) getConfigurationFromUser() (err error) {
 			case MTLS:
 				checkCertificateForMTLS(cc)
 				log.Warn("Please notice that authentication using client certificates (mTLS) is not supported by commands which integrate with package managers.")
+			case OIDC:
+				readOidcDetailsFromConsole(cc.details)
 			}
 		}
 
@@ -374,6 +382,7 @@ func promptAuthMethods() (method AuthenticationMethod, err error) {
 		BasicAuth,
 		AccessToken,
 		MTLS,
+		OIDC,
 	}
 	var selectableItems []ioutils.PromptItem
 	for _, method := range authMethod {
@@ -426,6 +435,14 @@ func readAccessTokenFromConsole(details *config.ServerDetails) error {
 	return err
 }
 
+func readOidcDetailsFromConsole(details *config.ServerDetails) {
+	ioutils.ScanFromConsole("OIDC provider name", &details.OidcProviderName, "")
+	ioutils.ScanFromConsole("OIDC ID token file path (optional)", &details.OidcTokenFile, "")
+	if details.OidcTokenFile == "" {
+		ioutils.ScanFromConsole("OIDC ID token environment variable", &details.OidcTokenEnvVar, coreutils.OidcIdToken)
+	}
+}
+
 func getSshKeyPath(details *config.ServerDetails) error {
 	// If path not provided as a key, read from console:
 	if details.SshKeyPath == "" {
@@ -519,6 +536,28 @@ func Export(serverName string) error {
 	return nil
 }
 
+// Exports the servers with the provided IDs as a bundle encrypted with the passphrase. If no IDs are provided, all servers are exported.
+func ExportBundle(serverIds []string, passphrase string) error {
+	bundle, err := config.ExportBundle(serverIds, passphrase)
+	if err != nil {
+		return err
+	}
+	log.Output(bundle)
+	return nil
+}
+
+// Imports the servers from a config bundle or a config token, handling existing servers according to the conflict policy.
+func ImportBundle(bundle, passphrase string, policy config.ImportConflictPolicy) error {
+	return runWithConfigLock("import bundle", func() error {
+		imported, err := config.ImportBundle(bundle, passphrase, policy)
+		if err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Imported %d server IDs: %s", len(imported), strings.Join(imported, ", ")))
+		return nil
+	})
+}
+
 func moveDefaultConfigToSliceEnd(configuration []*config.ServerDetails) []*config.ServerDetails {
 	lastIndex := len(configuration) - 1
 	// If configuration list has more than one config and the last one is not default, switch the last default config with the last one
@@ -555,6 +594,9 @@ func printConfigs(configuration []*config.ServerDetails) {
 		logIfNotEmpty(details.SshPassphrase, "SSH passphrase:\t\t\t", true, isDefault)
 		logIfNotEmpty(details.ClientCertPath, "Client certificate file path:\t", false, isDefault)
 		logIfNotEmpty(details.ClientCertKeyPath, "Client certificate key path:\t", false, isDefault)
+		logIfNotEmpty(details.OidcProviderName, "OIDC provider name:\t\t", false, isDefault)
+		logIfNotEmpty(details.OidcTokenFile, "OIDC ID token file path:\t", false, isDefault)
+		logIfNotEmpty(details.OidcTokenEnvVar, "OIDC ID token env var:\t\t", false, isDefault)
 		logIfNotEmpty(strconv.FormatBool(details.IsDefault), "Default:\t\t\t", false, isDefault)
 		log.Output()
 	}
@@ -738,10 +780,12 @@ func isUrlSafe(urlToCheck string) bool {
 func checkSingleAuthMethod(details *config.ServerDetails) error {
 	authMethods := []bool{
 		details.User != "" && details.Password != "",
-		details.AccessToken != "" && details.ArtifactoryRefreshToken == "",
-		details.SshKeyPath != ""}
+		// The access token of an OIDC server is the cached token, exchanged from the OIDC token.
+		details.AccessToken != "" && details.ArtifactoryRefreshToken == "" && details.OidcProviderName == "",
+		details.SshKeyPath != "",
+		details.OidcProviderName != ""}
 	if coreutils.SumTrueValues(authMethods) > 1 {
-		return errorutils.CheckErrorf("Only one authentication method is allowed: Username + Password/API key, RSA Token (SSH) or Access Token")
+		return errorutils.CheckErrorf("Only one authentication method is allowed: Username + Password/API key, RSA Token (SSH), Access Token or OIDC token exchange")
 	}
 	return nil
 }
@@ -81,6 +81,13 @@ func TestApiKeyInAccessToken(t *testing.T) {
 	assert.NoError(t, configCmd.Run())
 }
 
+func TestCheckSingleAuthMethodOidc(t *testing.T) {
+	// An OIDC server with a cached access token
+	assert.NoError(t, checkSingleAuthMethod(&config.ServerDetails{OidcProviderName: "provider", AccessToken: "cached-token"}))
+	assert.Error(t, checkSingleAuthMethod(&config.ServerDetails{OidcProviderName: "provider", User: "admin", Password: "password"}))
+	assert.Error(t, checkSingleAuthMethod(&config.ServerDetails{OidcProviderName: "provider", SshKeyPath: "/path/to/key"}))
+}
+
 func TestMTLS(t *testing.T) {
 	inputDetails := tests.CreateTestServerDetails()
 	inputDetails.ClientCertPath = "test/cert/path"
@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xraycommands "github.com/jfrog/jfrog-cli-core/v2/xray/commands"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	PingStatusOk     = "ok"
+	PingStatusFailed = "failed"
+)
+
+// ConfigDoctorCommand inspects the stored server configurations and reports the problems found.
+// The configurations are inspected locally. Optionally, the configured services are also pinged.
+type ConfigDoctorCommand struct {
+	serverId   string
+	ping       bool
+	jsonOutput bool
+	report     *ConfigDoctorReport
+}
+
+type ConfigDoctorReport struct {
+	Issues   []config.ConfigIssue `json:"issues"`
+	Pings    []ServicePingResult  `json:"pings,omitempty"`
+	Errors   int                  `json:"errors"`
+	Warnings int                  `json:"warnings"`
+}
+
+type ServicePingResult struct {
+	ServerId string `json:"serverId"`
+	Service  string `json:"service"`
+	Url      string `json:"url"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+}
+
+type configIssueTableRow struct {
+	ServerId string `col-name:"Server ID"`
+	Severity string `col-name:"Severity"`
+	Check    string `col-name:"Check"`
+	Message  string `col-name:"Message"`
+}
+
+type servicePingTableRow struct {
+	ServerId string `col-name:"Server ID"`
+	Service  string `col-name:"Service"`
+	Url      string `col-name:"URL"`
+	Status   string `col-name:"Status"`
+	Message  string `col-name:"Message"`
+}
+
+func NewConfigDoctorCommand() *ConfigDoctorCommand {
+	return &ConfigDoctorCommand{}
+}
+
+// Inspects only the server with the provided ID. If empty, all the servers are inspected.
+func (cdc *ConfigDoctorCommand) SetServerId(serverId string) *ConfigDoctorCommand {
+	cdc.serverId = serverId
+	return cdc
+}
+
+func (cdc *ConfigDoctorCommand) SetPing(ping bool) *ConfigDoctorCommand {
+	cdc.ping = ping
+	return cdc
+}
+
+func (cdc *ConfigDoctorCommand) SetJsonOutput(jsonOutput bool) *ConfigDoctorCommand {
+	cdc.jsonOutput = jsonOutput
+	return cdc
+}
+
+func (cdc *ConfigDoctorCommand) Report() *ConfigDoctorReport {
+	return cdc.report
+}
+
+func (cdc *ConfigDoctorCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (cdc *ConfigDoctorCommand) CommandName() string {
+	return "config_doctor"
+}
+
+func (cdc *ConfigDoctorCommand) Run() error {
+	servers, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	report := &ConfigDoctorReport{Issues: []config.ConfigIssue{}}
+	if cdc.serverId == "" {
+		report.Issues = append(report.Issues, config.DiagnoseServers(servers)...)
+	} else {
+		serverDetails, err := config.GetSpecificConfig(cdc.serverId, false, false)
+		if err != nil {
+			return err
+		}
+		servers = []*config.ServerDetails{serverDetails}
+		report.Issues = append(report.Issues, config.DiagnoseServer(serverDetails)...)
+	}
+	if cdc.ping {
+		for _, serverDetails := range servers {
+			report.Pings = append(report.Pings, pingServices(serverDetails)...)
+		}
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == config.ConfigIssueError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	for _, ping := range report.Pings {
+		if ping.Status == PingStatusFailed {
+			report.Errors++
+		}
+	}
+	cdc.report = report
+
+	if err = cdc.printReport(); err != nil {
+		return err
+	}
+	if report.Errors > 0 {
+		return errorutils.CheckErrorf("found %d errors in the server configurations", report.Errors)
+	}
+	return nil
+}
+
+func (cdc *ConfigDoctorCommand) printReport() error {
+	if cdc.jsonOutput {
+		content, err := json.Marshal(cdc.report)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(clientutils.IndentJson(content))
+		return nil
+	}
+	var issueRows []configIssueTableRow
+	for _, issue := range cdc.report.Issues {
+		issueRows = append(issueRows, configIssueTableRow{ServerId: issue.ServerId, Severity: string(issue.Severity), Check: issue.Check, Message: issue.Message})
+	}
+	if err := coreutils.PrintTable(issueRows, "Configuration Issues", "No configuration issues were found", false); err != nil {
+		return err
+	}
+	if !cdc.ping {
+		return nil
+	}
+	var pingRows []servicePingTableRow
+	for _, ping := range cdc.report.Pings {
+		pingRows = append(pingRows, servicePingTableRow(ping))
+	}
+	return coreutils.PrintTable(pingRows, "Services Connectivity", "No services are configured", false)
+}
+
+// Pings each of the services configured for the server.
+func pingServices(serverDetails *config.ServerDetails) (results []ServicePingResult) {
+	if serverDetails.ArtifactoryUrl != "" {
+		results = append(results, newServicePingResult(serverDetails, "Artifactory", serverDetails.ArtifactoryUrl, func() error {
+			servicesManager, err := utils.CreateServiceManager(serverDetails, -1, 0, false)
+			if err != nil {
+				return err
+			}
+			_, err = servicesManager.Ping()
+			return err
+		}))
+	}
+	if serverDetails.XrayUrl != "" {
+		results = append(results, newServicePingResult(serverDetails, "Xray", serverDetails.XrayUrl, func() error {
+			xrayManager, err := xraycommands.CreateXrayServiceManager(serverDetails)
+			if err != nil {
+				return err
+			}
+			_, err = xrayManager.GetVersion()
+			return err
+		}))
+	}
+	if serverDetails.Url != "" {
+		results = append(results, newServicePingResult(serverDetails, "Access", clientutils.AddTrailingSlashIfNeeded(serverDetails.Url)+"access/", func() error {
+			accessManager, err := utils.CreateAccessServiceManager(serverDetails, false)
+			if err != nil {
+				return err
+			}
+			_, err = accessManager.Ping()
+			return err
+		}))
+	}
+	return
+}
+
+func newServicePingResult(serverDetails *config.ServerDetails, service, url string, ping func() error) ServicePingResult {
+	log.Debug("Pinging " + service + " at " + url)
+	result := ServicePingResult{ServerId: serverDetails.ServerId, Service: service, Url: url, Status: PingStatusOk}
+	if err := ping(); err != nil {
+		result.Status = PingStatusFailed
+		result.Message = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	utilsTests "github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDoctor(t *testing.T) {
+	cleanUpJfrogHome, err := utilsTests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/artifactory/api/system/ping" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{
+		{ServerId: "healthy", Url: ts.URL + "/", ArtifactoryUrl: ts.URL + "/artifactory/", User: "admin", Password: "password", IsDefault: true},
+		{ServerId: "broken", ArtifactoryUrl: ts.URL + "/artifactory/", User: "admin", Password: "password", ClientCertPath: "/not/exist"},
+	}))
+
+	// Offline inspection of a healthy server
+	doctorCommand := NewConfigDoctorCommand().SetServerId("healthy").SetJsonOutput(true)
+	assert.NoError(t, doctorCommand.Run())
+	assert.Empty(t, doctorCommand.Report().Issues)
+	assert.Empty(t, doctorCommand.Report().Pings)
+
+	// Inspection of all servers, including pinging the services
+	doctorCommand = NewConfigDoctorCommand().SetPing(true)
+	assert.Error(t, doctorCommand.Run())
+	report := doctorCommand.Report()
+	// Missing client certificate key path, missing client certificate file and two failed pings
+	assert.Equal(t, 4, report.Errors)
+	// Missing platform URL
+	assert.Equal(t, 1, report.Warnings)
+	pingStatuses := make(map[string]string)
+	for _, ping := range report.Pings {
+		pingStatuses[ping.ServerId+"/"+ping.Service] = ping.Status
+	}
+	assert.Equal(t, map[string]string{
+		"healthy/Artifactory": PingStatusOk,
+		"healthy/Access":      PingStatusFailed,
+		"broken/Artifactory":  PingStatusFailed,
+	}, pingStatuses)
+}
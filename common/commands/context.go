@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Manages the config contexts - named profiles bundling a default server, project and build name.
+type ContextCommand struct {
+	cmdType ConfigAction
+	context *config.ContextDetails
+}
+
+func NewContextCommand(cmdType ConfigAction, name string) *ContextCommand {
+	return &ContextCommand{cmdType: cmdType, context: &config.ContextDetails{Name: name}}
+}
+
+func (cc *ContextCommand) SetServerId(serverId string) *ContextCommand {
+	cc.context.ServerId = serverId
+	return cc
+}
+
+func (cc *ContextCommand) SetProject(project string) *ContextCommand {
+	cc.context.Project = project
+	return cc
+}
+
+func (cc *ContextCommand) SetBuildName(buildName string) *ContextCommand {
+	cc.context.BuildName = buildName
+	return cc
+}
+
+func (cc *ContextCommand) Run() error {
+	return runWithConfigLock("context "+string(cc.cmdType), func() error {
+		switch cc.cmdType {
+		case AddOrEdit:
+			return config.SaveContext(cc.context)
+		case Delete:
+			return config.DeleteContext(cc.context.Name)
+		case Use:
+			if err := config.UseContext(cc.context.Name); err != nil {
+				return err
+			}
+			log.Info(fmt.Sprintf("Using context '%s'", cc.context.Name))
+			return nil
+		default:
+			return fmt.Errorf("Not supported context command type: " + string(cc.cmdType))
+		}
+	})
+}
+
+func (cc *ContextCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (cc *ContextCommand) CommandName() string {
+	return "config_context"
+}
+
+// Prints all the configured contexts, marking the active one.
+func ShowContexts() error {
+	contexts, err := config.GetAllContexts()
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		log.Output("No contexts have been configured.")
+		return nil
+	}
+	active, err := config.GetActiveContext()
+	if err != nil {
+		log.Warn("Couldn't resolve the active context: " + err.Error())
+	}
+	for _, context := range contexts {
+		isActive := active != nil && active.Name == context.Name
+		logIfNotEmpty(context.Name, "Context name:\t\t", false, isActive)
+		logIfNotEmpty(context.ServerId, "Server ID:\t\t", false, isActive)
+		logIfNotEmpty(context.Project, "Project:\t\t", false, isActive)
+		logIfNotEmpty(context.BuildName, "Build name:\t\t", false, isActive)
+		logIfNotEmpty(strconv.FormatBool(isActive), "Active:\t\t\t", false, isActive)
+		log.Output()
+	}
+	return nil
+}
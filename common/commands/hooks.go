@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// HookContext holds the details of a command execution, as passed to the registered hooks.
+type HookContext struct {
+	// The executed command.
+	Command Command
+	// The command name, as returned by Command.CommandName().
+	CommandName string
+	// The server details, as returned by Command.ServerDetails(). May be nil.
+	ServerDetails *config.ServerDetails
+	// The error returned by the command. Always nil in the pre-run phase.
+	Err error
+}
+
+// Hook allows running custom logic around every command executed by Exec.
+type Hook struct {
+	// A name identifying the hook in the logs.
+	Name string
+	// Invoked before the command runs. Returning an error aborts the command execution.
+	PreRun func(ctx *HookContext) error
+	// Invoked after the command runs, with the command's resulting error in ctx.Err.
+	PostRun func(ctx *HookContext) error
+}
+
+var (
+	hooks      []Hook
+	hooksMutex sync.RWMutex
+)
+
+// RegisterHook adds a hook to the chain executed around every command.
+// Pre-run hooks are invoked in registration order, post-run hooks in reverse order.
+func RegisterHook(hook Hook) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// ClearHooks removes all the registered hooks.
+func ClearHooks() {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	hooks = nil
+}
+
+func getHooks() []Hook {
+	hooksMutex.RLock()
+	defer hooksMutex.RUnlock()
+	return append([]Hook{}, hooks...)
+}
+
+func newHookContext(command Command) *HookContext {
+	ctx := &HookContext{Command: command, CommandName: command.CommandName()}
+	serverDetails, err := command.ServerDetails()
+	if err != nil {
+		log.Debug("Couldn't get the server details for the command hooks: " + err.Error())
+	}
+	ctx.ServerDetails = serverDetails
+	return ctx
+}
+
+func runPreHooks(registered []Hook, ctx *HookContext) error {
+	for _, hook := range registered {
+		if hook.PreRun == nil {
+			continue
+		}
+		log.Debug("Running pre-run hook:", hook.Name)
+		if err := hook.PreRun(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runs the post-run hooks. If the command failed, hooks errors are logged and the command's error is kept.
+// Otherwise, the first hook error is returned.
+func runPostHooks(registered []Hook, ctx *HookContext) (err error) {
+	for i := len(registered) - 1; i >= 0; i-- {
+		hook := registered[i]
+		if hook.PostRun == nil {
+			continue
+		}
+		log.Debug("Running post-run hook:", hook.Name)
+		hookErr := hook.PostRun(ctx)
+		if hookErr == nil {
+			continue
+		}
+		if ctx.Err != nil || err != nil {
+			log.Error("Post-run hook '" + hook.Name + "' failed: " + hookErr.Error())
+			continue
+		}
+		err = hookErr
+	}
+	return
+}
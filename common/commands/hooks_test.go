@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type hooksTestCommand struct {
+	runErr error
+	ran    bool
+}
+
+func (htc *hooksTestCommand) Run() error {
+	htc.ran = true
+	return htc.runErr
+}
+
+func (htc *hooksTestCommand) ServerDetails() (*config.ServerDetails, error) {
+	return &config.ServerDetails{ServerId: "test-server"}, nil
+}
+
+func (htc *hooksTestCommand) CommandName() string {
+	return "test_command"
+}
+
+func TestExecHooksOrder(t *testing.T) {
+	defer ClearHooks()
+	var calls []string
+	for _, name := range []string{"first", "second"} {
+		hookName := name
+		RegisterHook(Hook{
+			Name: hookName,
+			PreRun: func(ctx *HookContext) error {
+				assert.Equal(t, "test_command", ctx.CommandName)
+				assert.Equal(t, "test-server", ctx.ServerDetails.ServerId)
+				calls = append(calls, "pre-"+hookName)
+				return nil
+			},
+			PostRun: func(ctx *HookContext) error {
+				calls = append(calls, "post-"+hookName)
+				return nil
+			},
+		})
+	}
+	command := &hooksTestCommand{}
+	assert.NoError(t, Exec(command))
+	assert.True(t, command.ran)
+	assert.Equal(t, []string{"pre-first", "pre-second", "post-second", "post-first"}, calls)
+}
+
+func TestExecPreHookAbort(t *testing.T) {
+	defer ClearHooks()
+	policyErr := errors.New("policy violation")
+	postRunCalled := false
+	RegisterHook(Hook{
+		Name:    "policy",
+		PreRun:  func(*HookContext) error { return policyErr },
+		PostRun: func(*HookContext) error { postRunCalled = true; return nil },
+	})
+	command := &hooksTestCommand{}
+	assert.ErrorIs(t, Exec(command), policyErr)
+	assert.False(t, command.ran)
+	assert.False(t, postRunCalled)
+}
+
+func TestExecPostHookErrors(t *testing.T) {
+	defer ClearHooks()
+	hookErr := errors.New("hook error")
+	var receivedErr error
+	RegisterHook(Hook{
+		Name: "notify",
+		PostRun: func(ctx *HookContext) error {
+			receivedErr = ctx.Err
+			return hookErr
+		},
+	})
+	// A post-run hook error is returned when the command succeeds.
+	assert.ErrorIs(t, Exec(&hooksTestCommand{}), hookErr)
+	assert.NoError(t, receivedErr)
+
+	// The command's error is kept when the command fails.
+	runErr := errors.New("run error")
+	assert.ErrorIs(t, Exec(&hooksTestCommand{runErr: runErr}), runErr)
+	assert.ErrorIs(t, receivedErr, runErr)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Rotates the master key used for encrypting the config, or rolls back a previous rotation.
+type RotateKeyCommand struct {
+	newKey string
+	// If set, the rotation saved in this backup directory is rolled back.
+	rollbackBackupPath string
+	backupPath         string
+}
+
+func NewRotateKeyCommand() *RotateKeyCommand {
+	return &RotateKeyCommand{}
+}
+
+func (rkc *RotateKeyCommand) SetNewKey(newKey string) *RotateKeyCommand {
+	rkc.newKey = newKey
+	return rkc
+}
+
+func (rkc *RotateKeyCommand) SetRollbackBackupPath(rollbackBackupPath string) *RotateKeyCommand {
+	rkc.rollbackBackupPath = rollbackBackupPath
+	return rkc
+}
+
+// Returns the path to the backup created by the rotation.
+func (rkc *RotateKeyCommand) BackupPath() string {
+	return rkc.backupPath
+}
+
+func (rkc *RotateKeyCommand) Run() error {
+	return runWithConfigLock("rotate key", func() (err error) {
+		if rkc.rollbackBackupPath != "" {
+			if err = config.RestoreKeyRotationBackup(rkc.rollbackBackupPath); err != nil {
+				return
+			}
+			log.Info("The config was restored from:", rkc.rollbackBackupPath)
+			return
+		}
+		if rkc.backupPath, err = config.RotateEncryptionKey(rkc.newKey); err != nil {
+			return
+		}
+		log.Info("The master key was rotated successfully. To roll back, restore the backup at:", rkc.backupPath)
+		return
+	})
+}
+
+func (rkc *RotateKeyCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (rkc *RotateKeyCommand) CommandName() string {
+	return "config_rotate_key"
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// SpecValidateCommand validates file specs without running them, for linting specs in CI.
+// Each problem is logged in the '<spec-file>:<line>:<column>: <message>' format.
+type SpecValidateCommand struct {
+	specFiles []string
+	specVars  map[string]string
+	// The validation errors found, by spec file.
+	results map[string]spec.SpecValidationErrors
+}
+
+func NewSpecValidateCommand() *SpecValidateCommand {
+	return &SpecValidateCommand{}
+}
+
+func (svc *SpecValidateCommand) SetSpecFiles(specFiles []string) *SpecValidateCommand {
+	svc.specFiles = specFiles
+	return svc
+}
+
+func (svc *SpecValidateCommand) SetSpecVars(specVars map[string]string) *SpecValidateCommand {
+	svc.specVars = specVars
+	return svc
+}
+
+func (svc *SpecValidateCommand) Results() map[string]spec.SpecValidationErrors {
+	return svc.results
+}
+
+func (svc *SpecValidateCommand) Run() error {
+	if len(svc.specFiles) == 0 {
+		return errorutils.CheckErrorf("at least one spec file must be provided")
+	}
+	svc.results = make(map[string]spec.SpecValidationErrors)
+	invalidSpecs := 0
+	for _, specFile := range svc.specFiles {
+		validationErrors, err := spec.ValidateSpecFile(specFile, svc.specVars)
+		if err != nil {
+			return err
+		}
+		svc.results[specFile] = validationErrors
+		if len(validationErrors) == 0 {
+			log.Info(specFile + ": valid")
+			continue
+		}
+		invalidSpecs++
+		for _, validationError := range validationErrors {
+			// Errors found in included spec files are reported with the path of the included file.
+			errorFile := validationError.File
+			if errorFile == "" {
+				errorFile = specFile
+			}
+			log.Output(fmt.Sprintf("%s:%d:%d: %s", errorFile, validationError.Line, validationError.Column, validationError.Message))
+		}
+	}
+	if invalidSpecs > 0 {
+		return errorutils.CheckErrorf("%d of %d spec files are invalid", invalidSpecs, len(svc.specFiles))
+	}
+	return nil
+}
+
+func (svc *SpecValidateCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (svc *SpecValidateCommand) CommandName() string {
+	return "spec_validate"
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecValidateCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	validSpec := filepath.Join(tempDir, "valid.json")
+	assert.NoError(t, os.WriteFile(validSpec, []byte(`{"files": [{"pattern": "${REPO}/*", "target": "out/"}]}`), 0600))
+	invalidSpec := filepath.Join(tempDir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidSpec, []byte(`{"files": [{"patern": "repo/*", "build": "b/1", "bundle": "c/1"}]}`), 0600))
+
+	validateCommand := NewSpecValidateCommand().SetSpecFiles([]string{validSpec}).SetSpecVars(map[string]string{"REPO": "generic"})
+	assert.NoError(t, validateCommand.Run())
+	assert.Empty(t, validateCommand.Results()[validSpec])
+
+	validateCommand = NewSpecValidateCommand().SetSpecFiles([]string{validSpec, invalidSpec})
+	assert.EqualError(t, validateCommand.Run(), "1 of 2 spec files are invalid")
+	assert.Len(t, validateCommand.Results()[invalidSpec], 2)
+
+	assert.Error(t, NewSpecValidateCommand().SetSpecFiles([]string{filepath.Join(tempDir, "not-exist.json")}).Run())
+}
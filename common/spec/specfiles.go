@@ -5,11 +5,8 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
-	"github.com/jfrog/jfrog-client-go/utils/errorutils"
-	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 )
 
 type SpecFiles struct {
@@ -23,20 +20,20 @@ func (spec *SpecFiles) Get(index int) *File {
 	return new(File)
 }
 
+// Creates the spec from a JSON or YAML spec file.
+// The spec may include default values for its file groups' fields, and include other spec files.
 func CreateSpecFromFile(specFilePath string, specVars map[string]string) (spec *SpecFiles, err error) {
-	spec = new(SpecFiles)
-	content, err := fileutils.ReadFile(specFilePath)
-	if errorutils.CheckError(err) != nil {
+	content, err := readSpecContent(specFilePath, specVars)
+	if err != nil {
 		return
 	}
-
-	if len(specVars) > 0 {
-		content = coreutils.ReplaceVars(content, specVars)
+	if isYamlSpec(specFilePath) || isTemplatedJsonSpec(content) {
+		return createSpecFromTemplate(specFilePath, content, specVars)
 	}
 
-	err = json.Unmarshal(content, spec)
-	if errorutils.CheckError(err) != nil {
-		return
+	spec = new(SpecFiles)
+	if err = json.Unmarshal(content, spec); err != nil {
+		err = createSpecParsingError(specFilePath, content, err)
 	}
 	return
 }
@@ -0,0 +1,317 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The 1-based location of a value in a spec file.
+type nodePosition struct {
+	line   int
+	column int
+}
+
+func (p nodePosition) before(other nodePosition) bool {
+	return p.line < other.line || (p.line == other.line && p.column < other.column)
+}
+
+type jsonKind string
+
+const (
+	jsonObject jsonKind = "an object"
+	jsonArray  jsonKind = "an array"
+	jsonString jsonKind = "a string"
+	jsonNumber jsonKind = "a number"
+	jsonBool   jsonKind = "a boolean"
+	jsonNull   jsonKind = "null"
+)
+
+// A parsed JSON or YAML value, including its position in the spec file.
+type jsonNode struct {
+	kind jsonKind
+	pos  nodePosition
+	// The value of a string (string), number (json.Number) or boolean (bool).
+	value    interface{}
+	members  []jsonMember
+	elements []*jsonNode
+}
+
+type jsonMember struct {
+	key    string
+	keyPos nodePosition
+	value  *jsonNode
+}
+
+// Returns the first member with the provided key, matched case-insensitively, or nil if not found.
+func (n *jsonNode) getMember(key string) *jsonMember {
+	for i := range n.members {
+		if strings.EqualFold(n.members[i].key, key) {
+			return &n.members[i]
+		}
+	}
+	return nil
+}
+
+// Writes the node as JSON, while keeping the order of the objects' keys.
+func (n *jsonNode) writeJson(buffer *bytes.Buffer) error {
+	switch n.kind {
+	case jsonObject:
+		buffer.WriteByte('{')
+		for i, member := range n.members {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			key, err := json.Marshal(member.key)
+			if err != nil {
+				return err
+			}
+			buffer.Write(key)
+			buffer.WriteByte(':')
+			if err = member.value.writeJson(buffer); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	case jsonArray:
+		buffer.WriteByte('[')
+		for i, element := range n.elements {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := element.writeJson(buffer); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	default:
+		value, err := json.Marshal(n.value)
+		if err != nil {
+			return err
+		}
+		buffer.Write(value)
+	}
+	return nil
+}
+
+type specSyntaxError struct {
+	pos     nodePosition
+	message string
+}
+
+func (e *specSyntaxError) Error() string {
+	return e.message
+}
+
+func parseSpecNode(content []byte, isYaml bool) (*jsonNode, error) {
+	if isYaml {
+		return parseYamlNode(content)
+	}
+	return newJsonNodeParser(content).parse()
+}
+
+type jsonNodeParser struct {
+	content []byte
+	decoder *json.Decoder
+	// The offsets of the lines' beginnings, used to calculate positions.
+	lineOffsets []int
+}
+
+func newJsonNodeParser(content []byte) *jsonNodeParser {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	lineOffsets := []int{0}
+	for i, char := range content {
+		if char == '\n' {
+			lineOffsets = append(lineOffsets, i+1)
+		}
+	}
+	return &jsonNodeParser{content: content, decoder: decoder, lineOffsets: lineOffsets}
+}
+
+func (p *jsonNodeParser) parse() (*jsonNode, error) {
+	root, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.decoder.Token(); err != io.EOF {
+		return nil, &specSyntaxError{pos: p.position(p.nextTokenOffset()), message: "unexpected content after the end of the spec"}
+	}
+	return root, nil
+}
+
+func (p *jsonNodeParser) position(offset int) nodePosition {
+	line := sort.Search(len(p.lineOffsets), func(i int) bool {
+		return p.lineOffsets[i] > offset
+	})
+	return nodePosition{line: line, column: offset - p.lineOffsets[line-1] + 1}
+}
+
+// Returns the offset of the next token. The decoder's offset points to the end of the previous token,
+// which may be followed by whitespaces and delimiters.
+func (p *jsonNodeParser) nextTokenOffset() int {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.content) && strings.ContainsRune(" \t\r\n:,", rune(p.content[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func (p *jsonNodeParser) nextToken() (json.Token, nodePosition, error) {
+	offset := p.nextTokenOffset()
+	token, err := p.decoder.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, nodePosition{}, &specSyntaxError{pos: p.position(int(syntaxErr.Offset)), message: "invalid JSON: " + syntaxErr.Error()}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, nodePosition{}, &specSyntaxError{pos: p.position(len(p.content)), message: "invalid JSON: unexpected end of the spec"}
+		}
+		return nil, nodePosition{}, &specSyntaxError{pos: p.position(offset), message: "invalid JSON: " + err.Error()}
+	}
+	return token, p.position(offset), nil
+}
+
+func (p *jsonNodeParser) parseValue() (*jsonNode, error) {
+	token, pos, err := p.nextToken()
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonNode{pos: pos, value: token}
+	switch value := token.(type) {
+	case json.Delim:
+		node.value = nil
+		if value == '{' {
+			node.kind = jsonObject
+			err = p.parseMembers(node)
+		} else {
+			node.kind = jsonArray
+			err = p.parseElements(node)
+		}
+	case string:
+		node.kind = jsonString
+	case json.Number:
+		node.kind = jsonNumber
+	case bool:
+		node.kind = jsonBool
+	default:
+		node.kind = jsonNull
+	}
+	return node, err
+}
+
+func (p *jsonNodeParser) parseMembers(node *jsonNode) error {
+	for p.decoder.More() {
+		token, keyPos, err := p.nextToken()
+		if err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		node.members = append(node.members, jsonMember{key: token.(string), keyPos: keyPos, value: value})
+	}
+	// Consume the closing delimiter.
+	_, _, err := p.nextToken()
+	return err
+}
+
+func (p *jsonNodeParser) parseElements(node *jsonNode) error {
+	for p.decoder.More() {
+		element, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		node.elements = append(node.elements, element)
+	}
+	_, _, err := p.nextToken()
+	return err
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+func parseYamlNode(content []byte) (*jsonNode, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		pos := nodePosition{line: 1, column: 1}
+		if match := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); match != nil {
+			pos.line, _ = strconv.Atoi(match[1])
+		}
+		return nil, &specSyntaxError{pos: pos, message: "invalid YAML: " + strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+	if len(document.Content) == 0 {
+		return nil, &specSyntaxError{pos: nodePosition{line: 1, column: 1}, message: "the spec is empty"}
+	}
+	return convertYamlNode(document.Content[0])
+}
+
+func convertYamlNode(node *yaml.Node) (*jsonNode, error) {
+	converted := &jsonNode{pos: nodePosition{line: node.Line, column: node.Column}}
+	switch node.Kind {
+	case yaml.AliasNode:
+		return convertYamlNode(node.Alias)
+	case yaml.MappingNode:
+		converted.kind = jsonObject
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, &specSyntaxError{pos: nodePosition{line: key.Line, column: key.Column}, message: "invalid YAML: keys must be strings"}
+			}
+			convertedValue, err := convertYamlNode(value)
+			if err != nil {
+				return nil, err
+			}
+			converted.members = append(converted.members, jsonMember{key: key.Value, keyPos: nodePosition{line: key.Line, column: key.Column}, value: convertedValue})
+		}
+	case yaml.SequenceNode:
+		converted.kind = jsonArray
+		for _, element := range node.Content {
+			convertedElement, err := convertYamlNode(element)
+			if err != nil {
+				return nil, err
+			}
+			converted.elements = append(converted.elements, convertedElement)
+		}
+	default:
+		return convertYamlScalar(node, converted)
+	}
+	return converted, nil
+}
+
+func convertYamlScalar(node *yaml.Node, converted *jsonNode) (*jsonNode, error) {
+	switch node.ShortTag() {
+	case "!!int":
+		// Decoded as an integer, to keep the precision of large values.
+		var number int64
+		if err := node.Decode(&number); err != nil {
+			return nil, &specSyntaxError{pos: converted.pos, message: "invalid YAML: " + err.Error()}
+		}
+		converted.kind, converted.value = jsonNumber, json.Number(strconv.FormatInt(number, 10))
+	case "!!float":
+		var number float64
+		if err := node.Decode(&number); err != nil {
+			return nil, &specSyntaxError{pos: converted.pos, message: "invalid YAML: " + err.Error()}
+		}
+		converted.kind, converted.value = jsonNumber, json.Number(strconv.FormatFloat(number, 'f', -1, 64))
+	case "!!bool":
+		var value bool
+		if err := node.Decode(&value); err != nil {
+			return nil, &specSyntaxError{pos: converted.pos, message: "invalid YAML: " + err.Error()}
+		}
+		converted.kind, converted.value = jsonBool, value
+	case "!!null":
+		converted.kind = jsonNull
+	default:
+		converted.kind, converted.value = jsonString, node.Value
+	}
+	return converted, nil
+}
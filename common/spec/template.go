@@ -0,0 +1,212 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+// Besides the file groups, a spec may include default values for the fields of its file groups,
+// and paths of other spec files, whose file groups are added to the spec.
+const (
+	specFilesKey    = "files"
+	specDefaultsKey = "defaults"
+	specIncludeKey  = "include"
+)
+
+// Returns true if the spec file is a YAML file, according to its extension.
+func isYamlSpec(specFilePath string) bool {
+	extension := strings.ToLower(filepath.Ext(specFilePath))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+func readSpecContent(specFilePath string, specVars map[string]string) ([]byte, error) {
+	content, err := fileutils.ReadFile(specFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if len(specVars) > 0 {
+		content = coreutils.ReplaceVars(content, specVars)
+	}
+	return content, nil
+}
+
+// Returns true if the JSON spec uses defaults or includes other specs.
+// Other JSON specs are parsed directly into SpecFiles, as they always were.
+func isTemplatedJsonSpec(content []byte) bool {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(content, &root); err != nil {
+		return false
+	}
+	for key := range root {
+		if strings.EqualFold(key, specDefaultsKey) || strings.EqualFold(key, specIncludeKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validates the spec file and the spec files it includes, after replacing the spec vars.
+// YAML spec files are supported as well.
+func ValidateSpecFile(specFilePath string, specVars map[string]string) (SpecValidationErrors, error) {
+	content, err := readSpecContent(specFilePath, specVars)
+	if err != nil {
+		return nil, err
+	}
+	_, validationErrors, err := loadSpecFileGroups(specFilePath, content, specVars, nil, false)
+	return validationErrors, err
+}
+
+// Creates the spec from a YAML spec, or from a JSON spec which uses defaults or includes other specs.
+// Only errors which prevent resolving the defaults and the includes fail the spec's loading. The resolved file groups are
+// then parsed as plain JSON specs are, so that a file group is handled the same way regardless of the spec it appears in.
+func createSpecFromTemplate(specFilePath string, content []byte, specVars map[string]string) (*SpecFiles, error) {
+	fileGroups, validationErrors, err := loadSpecFileGroups(specFilePath, content, specVars, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(validationErrors) > 0 {
+		return nil, errorutils.CheckErrorf("the spec file '%s' is invalid:\n%s", specFilePath, validationErrors.Error())
+	}
+	resolved := &jsonNode{kind: jsonObject, members: []jsonMember{{key: specFilesKey, value: &jsonNode{kind: jsonArray, elements: fileGroups}}}}
+	buffer := new(bytes.Buffer)
+	if err = resolved.writeJson(buffer); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	spec := new(SpecFiles)
+	if err = json.Unmarshal(buffer.Bytes(), spec); err != nil {
+		// Similarly to createSpecParsingError, describe the schema violations which failed the parsing.
+		if validationErrors, e := ValidateSpecFile(specFilePath, specVars); e == nil && len(validationErrors) > 0 {
+			return nil, errorutils.CheckErrorf("the spec file '%s' is invalid:\n%s", specFilePath, validationErrors.Error())
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	return spec, nil
+}
+
+// Validates the spec file and the files it includes, and returns the file groups of all the files, with the defaults applied.
+// includeStack holds the paths of the spec files which include the current file, to detect circular includes.
+// If structureOnly is true, only the errors which prevent resolving the file groups are returned.
+func loadSpecFileGroups(specFilePath string, content []byte, specVars map[string]string, includeStack []string, structureOnly bool) ([]*jsonNode, SpecValidationErrors, error) {
+	validator := &specValidator{file: specFilePath, structureOnly: structureOnly}
+	root, err := parseSpecNode(content, isYamlSpec(specFilePath))
+	if err != nil {
+		validator.addSyntaxError(err)
+		return nil, validator.errors, nil
+	}
+	if isYamlSpec(specFilePath) {
+		convertBoolsToStrings(root)
+	}
+	validator.validateSpec(root)
+	if len(validator.errors) > 0 {
+		return nil, validator.errors, nil
+	}
+
+	var fileGroups []*jsonNode
+	if files := root.getMember(specFilesKey); files != nil {
+		fileGroups = append(fileGroups, files.value.elements...)
+	}
+	absolutePath, err := filepath.Abs(specFilePath)
+	if err != nil {
+		return nil, nil, errorutils.CheckError(err)
+	}
+	includeStack = append(includeStack, absolutePath)
+	for _, include := range getIncludedPaths(root) {
+		includedFileGroups, includedErrors, err := loadIncludedSpecFile(filepath.Dir(absolutePath), include, specVars, includeStack, validator)
+		if err != nil {
+			return nil, nil, err
+		}
+		fileGroups = append(fileGroups, includedFileGroups...)
+		validator.errors = append(validator.errors, includedErrors...)
+	}
+	if len(validator.errors) > 0 {
+		return nil, validator.errors, nil
+	}
+	if len(includeStack) == 1 && len(fileGroups) == 0 && !structureOnly {
+		validator.addError(root.pos, "spec must include at least one file group")
+		return nil, validator.errors, nil
+	}
+	if defaults := root.getMember(specDefaultsKey); defaults != nil {
+		for i, fileGroup := range fileGroups {
+			fileGroups[i] = applyDefaults(fileGroup, defaults.value)
+		}
+	}
+	return fileGroups, nil, nil
+}
+
+func loadIncludedSpecFile(baseDir string, include *jsonNode, specVars map[string]string, includeStack []string, validator *specValidator) ([]*jsonNode, SpecValidationErrors, error) {
+	includedPath := include.value.(string)
+	if !filepath.IsAbs(includedPath) {
+		includedPath = filepath.Join(baseDir, includedPath)
+	}
+	for _, includingPath := range includeStack {
+		if includingPath == includedPath {
+			validator.addError(include.pos, "circular include of '%s'", include.value)
+			return nil, nil, nil
+		}
+	}
+	exists, err := fileutils.IsFileExists(includedPath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		validator.addError(include.pos, "the included spec file '%s' does not exist", include.value)
+		return nil, nil, nil
+	}
+	content, err := readSpecContent(includedPath, specVars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loadSpecFileGroups(includedPath, content, specVars, includeStack, validator.structureOnly)
+}
+
+func getIncludedPaths(root *jsonNode) []*jsonNode {
+	include := root.getMember(specIncludeKey)
+	if include == nil {
+		return nil
+	}
+	if include.value.kind == jsonString {
+		return []*jsonNode{include.value}
+	}
+	return include.value.elements
+}
+
+// Returns a copy of the file group, with the default values of the fields the file group doesn't set.
+func applyDefaults(fileGroup, defaults *jsonNode) *jsonNode {
+	merged := &jsonNode{kind: jsonObject, pos: fileGroup.pos, members: append([]jsonMember{}, fileGroup.members...)}
+	for _, member := range defaults.members {
+		if fileGroup.getMember(member.key) == nil {
+			merged.members = append(merged.members, member)
+		}
+	}
+	return merged
+}
+
+// YAML booleans are natural to use in YAML specs, while the spec's boolean fields are strings.
+// Converts the booleans of the file groups and the defaults to strings. Values inside AQL queries are kept.
+func convertBoolsToStrings(root *jsonNode) {
+	if root.kind != jsonObject {
+		return
+	}
+	var nodes []*jsonNode
+	if defaults := root.getMember(specDefaultsKey); defaults != nil {
+		nodes = append(nodes, defaults.value)
+	}
+	if files := root.getMember(specFilesKey); files != nil {
+		nodes = append(nodes, files.value.elements...)
+	}
+	for _, node := range nodes {
+		for i := range node.members {
+			value := node.members[i].value
+			if _, fieldType, exists := getFileSpecField(node.members[i].key); exists && fieldType == boolStringField && value.kind == jsonBool {
+				value.kind, value.value = jsonString, strconv.FormatBool(value.value.(bool))
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSpecFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestCreateSpecFromYamlFile(t *testing.T) {
+	specFile := writeSpecFile(t, t.TempDir(), "spec.yaml", `
+defaults:
+  recursive: false
+  target: ${TARGET}/
+files:
+  - pattern: generic/*.zip
+    flat: true
+  - pattern: generic/*.tgz
+    target: other/
+    limit: 3
+  - aql:
+      items.find:
+        repo: generic
+        "$or":
+          - name: "a.zip"
+`)
+	spec, err := CreateSpecFromFile(specFile, map[string]string{"TARGET": "out"})
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 3) {
+		assert.Equal(t, "generic/*.zip", spec.Files[0].Pattern)
+		assert.Equal(t, "true", spec.Files[0].Flat)
+		assert.Equal(t, "false", spec.Files[0].Recursive)
+		assert.Equal(t, "out/", spec.Files[0].Target)
+		assert.Equal(t, "other/", spec.Files[1].Target)
+		assert.Equal(t, 3, spec.Files[1].Limit)
+		assert.Equal(t, `{"repo":"generic","$or":[{"name":"a.zip"}]}`, spec.Files[2].Aql.ItemsFind)
+		assert.Equal(t, "out/", spec.Files[2].Target)
+	}
+}
+
+func TestCreateSpecWithDefaultsAndIncludes(t *testing.T) {
+	tempDir := t.TempDir()
+	writeSpecFile(t, tempDir, "common/base.yml", `
+defaults:
+  recursive: "false"
+  props: a=1
+files:
+  - pattern: generic/base/*
+`)
+	specFile := writeSpecFile(t, tempDir, "spec.json", `{
+  "include": "common/base.yml",
+  "defaults": {"target": "out/", "props": "b=2"},
+  "files": [{"pattern": "generic/main/*", "target": "main/"}]
+}`)
+	spec, err := CreateSpecFromFile(specFile, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 2) {
+		// The file group's values override the defaults.
+		assert.Equal(t, "main/", spec.Files[0].Target)
+		assert.Equal(t, "b=2", spec.Files[0].Props)
+		// The included spec's defaults override the including spec's defaults.
+		assert.Equal(t, "generic/base/*", spec.Files[1].Pattern)
+		assert.Equal(t, "false", spec.Files[1].Recursive)
+		assert.Equal(t, "a=1", spec.Files[1].Props)
+		assert.Equal(t, "out/", spec.Files[1].Target)
+	}
+}
+
+func TestValidateSpecFileIncludes(t *testing.T) {
+	tempDir := t.TempDir()
+	firstSpec := writeSpecFile(t, tempDir, "first.json", `{"include": ["second.json", "missing.json"]}`)
+	secondSpec := writeSpecFile(t, tempDir, "second.json", `{"include": "first.json", "files": [{"patern": "a"}]}`)
+
+	validationErrors, err := ValidateSpecFile(firstSpec, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SpecValidationErrors{
+		{File: secondSpec, Line: 1, Column: 38, Message: "unknown key 'patern', did you mean 'pattern'?"},
+		{File: firstSpec, Line: 1, Column: 29, Message: "the included spec file 'missing.json' does not exist"},
+	}, validationErrors)
+
+	writeSpecFile(t, tempDir, "second.json", `{"include": "first.json", "files": [{"pattern": "a"}]}`)
+	validationErrors, err = ValidateSpecFile(firstSpec, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SpecValidationErrors{
+		{File: secondSpec, Line: 1, Column: 13, Message: "circular include of 'first.json'"},
+		{File: firstSpec, Line: 1, Column: 29, Message: "the included spec file 'missing.json' does not exist"},
+	}, validationErrors)
+
+	_, err = CreateSpecFromFile(firstSpec, nil)
+	assert.ErrorContains(t, err, "circular include of 'first.json'")
+}
+
+func TestValidateYamlSpecFile(t *testing.T) {
+	tempDir := t.TempDir()
+	specFile := writeSpecFile(t, tempDir, "spec.yaml", "files:\n  - pattern: a\n    limit: many\n    regexp: true\n")
+	validationErrors, err := ValidateSpecFile(specFile, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SpecValidationErrors{
+		{File: specFile, Line: 3, Column: 12, Message: "'limit' must be a number, but a string was found"},
+	}, validationErrors)
+
+	specFile = writeSpecFile(t, tempDir, "invalid.yml", "files:\n\t- pattern: a\n")
+	validationErrors, err = ValidateSpecFile(specFile, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, validationErrors, 1) {
+		assert.Equal(t, 2, validationErrors[0].Line)
+		assert.Contains(t, validationErrors[0].Message, "invalid YAML")
+	}
+}
+
+func TestCreateSpecFileGroupConsistency(t *testing.T) {
+	tempDir := t.TempDir()
+	// The same file groups are loaded similarly from a plain JSON spec, a JSON spec with defaults and a YAML spec.
+	specFiles := []string{
+		writeSpecFile(t, tempDir, "plain.json", `{"files": [{"pattern": "a/*", "unknown": "value"}]}`),
+		writeSpecFile(t, tempDir, "defaults.json", `{"defaults": {"flat": "true"}, "files": [{"pattern": "a/*", "unknown": "value"}]}`),
+		writeSpecFile(t, tempDir, "spec.yaml", "files:\n  - pattern: a/*\n    unknown: value\n"),
+	}
+	for _, specFile := range specFiles {
+		spec, err := CreateSpecFromFile(specFile, nil)
+		assert.NoError(t, err, specFile)
+		if assert.Len(t, spec.Files, 1, specFile) {
+			assert.Equal(t, "a/*", spec.Files[0].Pattern)
+		}
+	}
+
+	specFiles = []string{
+		writeSpecFile(t, tempDir, "plain.json", `{"files": [{"pattern": "a/*", "limit": "many"}]}`),
+		writeSpecFile(t, tempDir, "defaults.json", `{"defaults": {"flat": "true"}, "files": [{"pattern": "a/*", "limit": "many"}]}`),
+		writeSpecFile(t, tempDir, "spec.yaml", "files:\n  - pattern: a/*\n    limit: many\n"),
+	}
+	for _, specFile := range specFiles {
+		_, err := CreateSpecFromFile(specFile, nil)
+		assert.ErrorContains(t, err, "'limit' must be a number", specFile)
+	}
+}
+
+func TestCreateSpecFromYamlFileLargeInt(t *testing.T) {
+	specFile := writeSpecFile(t, t.TempDir(), "spec.yaml", "files:\n  - pattern: a/*\n    offset: 9007199254740993\n")
+	spec, err := CreateSpecFromFile(specFile, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 1) {
+		assert.EqualValues(t, int64(9007199254740993), spec.Files[0].Offset)
+	}
+}
@@ -0,0 +1,415 @@
+package spec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// The JSON Schema of file specs, for use by editors and external tools.
+//
+//go:embed filespec.schema.json
+var FileSpecSchema []byte
+
+type specFieldType int
+
+const (
+	stringField specFieldType = iota
+	// A string holding a boolean value, such as "true".
+	boolStringField
+	intField
+	stringArrayField
+	aqlField
+)
+
+// The fields of a file group in a spec, as they appear in the spec file. Keys are matched case-insensitively, similarly to json.Unmarshal.
+var fileSpecFields = map[string]specFieldType{
+	"aql":                 aqlField,
+	"pattern":             stringField,
+	"exclusions":          stringArrayField,
+	"target":              stringField,
+	"explode":             boolStringField,
+	"props":               stringField,
+	"targetProps":         stringField,
+	"excludeProps":        stringField,
+	"sortOrder":           stringField,
+	"sortBy":              stringArrayField,
+	"offset":              intField,
+	"limit":               intField,
+	"build":               stringField,
+	"project":             stringField,
+	"excludeArtifacts":    boolStringField,
+	"includeDeps":         boolStringField,
+	"bundle":              stringField,
+	"gpg-key":             stringField,
+	"recursive":           boolStringField,
+	"flat":                boolStringField,
+	"regexp":              boolStringField,
+	"ant":                 boolStringField,
+	"includeDirs":         boolStringField,
+	"archiveEntries":      stringField,
+	"validateSymlinks":    boolStringField,
+	"symlinks":            boolStringField,
+	"archive":             stringField,
+	"transitive":          boolStringField,
+	"targetPathInArchive": stringField,
+}
+
+// Pairs of fields which cannot be used together in the same file group.
+var mutuallyExclusiveFields = [][2]string{
+	{"aql", "pattern"},
+	{"aql", "exclusions"},
+	{"aql", "excludeProps"},
+	{"build", "bundle"},
+	{"build", "offset"},
+	{"build", "limit"},
+	{"bundle", "offset"},
+	{"bundle", "limit"},
+	{"transitive", "offset"},
+	{"transitive", "sortBy"},
+	{"regexp", "ant"},
+}
+
+// Fields which can only be used if another field is used.
+var dependentFields = [][2]string{
+	{"sortOrder", "sortBy"},
+	{"excludeArtifacts", "build"},
+	{"includeDeps", "build"},
+	{"gpg-key", "bundle"},
+}
+
+type SpecValidationError struct {
+	// The spec file path. Empty when validating content rather than a file.
+	File string
+	// The 1-based location of the error in the spec file.
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e SpecValidationError) Error() string {
+	location := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ", " + location
+	}
+	return location + ": " + e.Message
+}
+
+type SpecValidationErrors []SpecValidationError
+
+func (errs SpecValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Validates the content of a JSON spec, and returns the unknown keys, wrong types and conflicting fields, with their locations.
+// Requirements which depend on the command using the spec, such as a mandatory target, are validated by ValidateSpec.
+// Included spec files are not validated, use ValidateSpecFile to validate them as well.
+func ValidateSpecContent(content []byte) SpecValidationErrors {
+	validator := new(specValidator)
+	root, err := parseSpecNode(content, false)
+	if err != nil {
+		validator.addSyntaxError(err)
+		return validator.errors
+	}
+	validator.validateSpec(root)
+	return validator.errors
+}
+
+type specValidator struct {
+	// The spec file path, reported with the errors.
+	file string
+	// Validate only the spec's structure - the kinds of the files, defaults and include values, ignoring the file groups' fields.
+	structureOnly bool
+	errors        SpecValidationErrors
+}
+
+func (sv *specValidator) addError(pos nodePosition, format string, args ...interface{}) {
+	sv.errors = append(sv.errors, SpecValidationError{File: sv.file, Line: pos.line, Column: pos.column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (sv *specValidator) addSyntaxError(err error) {
+	var syntaxErr *specSyntaxError
+	if errors.As(err, &syntaxErr) {
+		sv.addError(syntaxErr.pos, syntaxErr.message)
+	} else {
+		sv.addError(nodePosition{line: 1, column: 1}, err.Error())
+	}
+}
+
+func (sv *specValidator) validateSpec(root *jsonNode) {
+	if root.kind != jsonObject {
+		sv.addError(root.pos, "the spec must be an object")
+		return
+	}
+	var files, defaults, include *jsonNode
+	for _, member := range root.members {
+		switch strings.ToLower(member.key) {
+		case specFilesKey:
+			files = member.value
+		case specDefaultsKey:
+			defaults = member.value
+		case specIncludeKey:
+			include = member.value
+		default:
+			if sv.structureOnly {
+				continue
+			}
+			sv.addError(member.keyPos, "unknown key '%s'%s", member.key, suggestKey(member.key, []string{specFilesKey, specDefaultsKey, specIncludeKey}))
+		}
+	}
+	if include != nil {
+		sv.validateInclude(include)
+	}
+	var defaultsFields map[string]*jsonMember
+	if defaults != nil {
+		defaultsFields = sv.validateDefaults(defaults)
+	}
+	if files == nil {
+		// The file groups may be provided by the included specs alone.
+		if include == nil && !sv.structureOnly {
+			sv.addError(root.pos, "the spec must include the 'files' key")
+		}
+		return
+	}
+	if files.kind != jsonArray {
+		sv.addError(files.pos, "'files' must be an array")
+		return
+	}
+	if len(files.elements) == 0 && include == nil && !sv.structureOnly {
+		sv.addError(files.pos, "spec must include at least one file group")
+	}
+	for _, fileGroup := range files.elements {
+		sv.validateFileGroup(fileGroup, defaultsFields)
+	}
+}
+
+func (sv *specValidator) validateInclude(include *jsonNode) {
+	if include.kind == jsonString {
+		return
+	}
+	if include.kind != jsonArray {
+		sv.addError(include.pos, "'include' must be a path or an array of paths, but %s was found", include.kind)
+		return
+	}
+	for _, element := range include.elements {
+		if element.kind != jsonString {
+			sv.addError(element.pos, "'include' must be an array of paths, but it includes %s", element.kind)
+		}
+	}
+}
+
+// Validates the default values of the file groups' fields, and returns the fields which are set.
+func (sv *specValidator) validateDefaults(defaults *jsonNode) map[string]*jsonMember {
+	if defaults.kind != jsonObject {
+		sv.addError(defaults.pos, "'defaults' must be an object, but %s was found", defaults.kind)
+		return nil
+	}
+	if sv.structureOnly {
+		return nil
+	}
+	return sv.validateFields(defaults, nil)
+}
+
+// Validates the fields of a file group or of the defaults, and returns the fields which are set, by their canonical names.
+// The fields inherited from the defaults are included, unless the file group overrides them.
+func (sv *specValidator) validateFields(node *jsonNode, defaultsFields map[string]*jsonMember) map[string]*jsonMember {
+	setFields := make(map[string]*jsonMember)
+	for field, member := range defaultsFields {
+		setFields[field] = member
+	}
+	seenFields := make(map[string]bool)
+	for i := range node.members {
+		member := &node.members[i]
+		field, fieldType, exists := getFileSpecField(member.key)
+		if !exists {
+			sv.addError(member.keyPos, "unknown key '%s'%s", member.key, suggestKey(member.key, getFileSpecFieldNames()))
+			continue
+		}
+		if seenFields[field] {
+			sv.addError(member.keyPos, "duplicate key '%s'", member.key)
+			continue
+		}
+		seenFields[field] = true
+		delete(setFields, field)
+		if sv.validateFieldType(field, fieldType, member.value) && isFieldSet(fieldType, member.value) {
+			setFields[field] = member
+		}
+	}
+	return setFields
+}
+
+func (sv *specValidator) validateFileGroup(fileGroup *jsonNode, defaultsFields map[string]*jsonMember) {
+	if fileGroup.kind != jsonObject {
+		sv.addError(fileGroup.pos, "a file group must be an object")
+		return
+	}
+	if sv.structureOnly {
+		return
+	}
+	setFields := sv.validateFields(fileGroup, defaultsFields)
+	for _, fields := range mutuallyExclusiveFields {
+		first, second := setFields[fields[0]], setFields[fields[1]]
+		if first != nil && second != nil {
+			// Report the field which appears last.
+			if second.keyPos.before(first.keyPos) {
+				second = first
+			}
+			sv.addError(second.keyPos, "'%s' and '%s' cannot be used together", fields[0], fields[1])
+		}
+	}
+	for _, fields := range dependentFields {
+		if member := setFields[fields[0]]; member != nil && setFields[fields[1]] == nil {
+			sv.addError(member.keyPos, "'%s' can only be used together with '%s'", fields[0], fields[1])
+		}
+	}
+	if member := setFields["sortOrder"]; member != nil && member.value.value != "asc" && member.value.value != "desc" {
+		sv.addError(member.value.pos, "the value of 'sortOrder' can only be 'asc' or 'desc'")
+	}
+	if member := setFields["archive"]; member != nil && member.value.value != "zip" {
+		sv.addError(member.value.pos, "the value of 'archive' can only be 'zip'")
+	}
+}
+
+// Returns false if the value doesn't match the field's type.
+func (sv *specValidator) validateFieldType(field string, fieldType specFieldType, value *jsonNode) bool {
+	switch fieldType {
+	case stringField:
+		if value.kind != jsonString {
+			sv.addError(value.pos, "'%s' must be a string, but %s was found", field, value.kind)
+			return false
+		}
+	case boolStringField:
+		if value.kind != jsonString {
+			sv.addError(value.pos, "'%s' must be a string holding a boolean, such as \"true\", but %s was found", field, value.kind)
+			return false
+		}
+		if _, err := strconv.ParseBool(value.value.(string)); err != nil && value.value != "" {
+			sv.addError(value.pos, "'%s' must be \"true\" or \"false\", but \"%s\" was found", field, value.value)
+			return false
+		}
+	case intField:
+		if value.kind != jsonNumber {
+			sv.addError(value.pos, "'%s' must be a number, but %s was found", field, value.kind)
+			return false
+		}
+		if number, err := strconv.Atoi(value.value.(json.Number).String()); err != nil || number < 0 {
+			sv.addError(value.pos, "'%s' must be a non-negative integer", field)
+			return false
+		}
+	case stringArrayField:
+		if value.kind != jsonArray {
+			sv.addError(value.pos, "'%s' must be an array of strings, but %s was found", field, value.kind)
+			return false
+		}
+		for _, element := range value.elements {
+			if element.kind != jsonString {
+				sv.addError(element.pos, "'%s' must be an array of strings, but it includes %s", field, element.kind)
+				return false
+			}
+		}
+	case aqlField:
+		if value.kind != jsonObject || len(value.members) != 1 || value.members[0].key != "items.find" {
+			sv.addError(value.pos, "'%s' must be an object with a single 'items.find' key", field)
+			return false
+		}
+		if itemsFind := value.members[0].value; itemsFind.kind != jsonObject {
+			sv.addError(itemsFind.pos, "'items.find' must be an object, but %s was found", itemsFind.kind)
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the field has a value which affects the spec, similarly to ValidateSpec.
+func isFieldSet(fieldType specFieldType, value *jsonNode) bool {
+	switch fieldType {
+	case stringField:
+		return value.value != ""
+	case boolStringField:
+		isTrue, _ := strconv.ParseBool(value.value.(string))
+		return isTrue
+	case intField:
+		number, _ := strconv.Atoi(value.value.(json.Number).String())
+		return number > 0
+	case stringArrayField:
+		return len(value.elements) > 0 && value.elements[0].value != ""
+	}
+	return true
+}
+
+func getFileSpecField(key string) (field string, fieldType specFieldType, exists bool) {
+	for field, fieldType = range fileSpecFields {
+		if strings.EqualFold(field, key) {
+			return field, fieldType, true
+		}
+	}
+	return "", 0, false
+}
+
+func getFileSpecFieldNames() []string {
+	fields := make([]string, 0, len(fileSpecFields))
+	for field := range fileSpecFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Returns a suggestion for a misspelled key, if a similar known key exists.
+func suggestKey(key string, knownKeys []string) string {
+	for _, knownKey := range knownKeys {
+		if editDistance(strings.ToLower(key), strings.ToLower(knownKey)) <= 2 {
+			return fmt.Sprintf(", did you mean '%s'?", knownKey)
+		}
+	}
+	return ""
+}
+
+// Returns the Levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// Returns an error describing the spec's schema violations, if json.Unmarshal failed to parse the spec.
+func createSpecParsingError(specFilePath string, content []byte, unmarshalErr error) error {
+	if validationErrors := ValidateSpecContent(content); len(validationErrors) > 0 {
+		return errorutils.CheckErrorf("the spec file '%s' is invalid:\n%s", specFilePath, validationErrors.Error())
+	}
+	return errorutils.CheckError(unmarshalErr)
+}
@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSpecContent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected SpecValidationErrors
+	}{
+		{"valid", `{"files": [{"pattern": "repo/*", "target": "dir/", "recursive": "false", "Flat": "true", "exclusions": ["*.tmp"], "limit": 3, "sortBy": ["name"], "sortOrder": "asc"}]}`, nil},
+		{"validAql", `{"files": [{"aql": {"items.find": {"repo": "generic"}}, "build": "name/1"}]}`, nil},
+		{"conflictingFalseValues", `{"files": [{"pattern": "a", "regexp": "false", "ant": "true"}]}`, nil},
+		{"unknownKey", "{\n  \"files\": [\n    {\n      \"patern\": \"repo/*\"\n    }\n  ]\n}", SpecValidationErrors{
+			{Line: 4, Column: 7, Message: "unknown key 'patern', did you mean 'pattern'?"},
+		}},
+		{"unknownRootKey", `{"file": []}`, SpecValidationErrors{
+			{Line: 1, Column: 2, Message: "unknown key 'file', did you mean 'files'?"},
+			{Line: 1, Column: 1, Message: "the spec must include the 'files' key"},
+		}},
+		{"wrongTypes", "{\"files\": [{\n\"pattern\": \"a\",\n\"recursive\": true,\n\"limit\": \"3\",\n\"offset\": -1,\n\"exclusions\": \"b\",\n\"flat\": \"yes\"\n}]}", SpecValidationErrors{
+			{Line: 3, Column: 14, Message: "'recursive' must be a string holding a boolean, such as \"true\", but a boolean was found"},
+			{Line: 4, Column: 10, Message: "'limit' must be a number, but a string was found"},
+			{Line: 5, Column: 11, Message: "'offset' must be a non-negative integer"},
+			{Line: 6, Column: 15, Message: "'exclusions' must be an array of strings, but a string was found"},
+			{Line: 7, Column: 9, Message: "'flat' must be \"true\" or \"false\", but \"yes\" was found"},
+		}},
+		{"mutuallyExclusive", "{\"files\": [\n{\"aql\": {\"items.find\": {}}, \"pattern\": \"a\"},\n{\"bundle\": \"b/1\", \"build\": \"c/1\", \"limit\": 1}\n]}", SpecValidationErrors{
+			{Line: 2, Column: 29, Message: "'aql' and 'pattern' cannot be used together"},
+			{Line: 3, Column: 19, Message: "'build' and 'bundle' cannot be used together"},
+			{Line: 3, Column: 35, Message: "'build' and 'limit' cannot be used together"},
+			{Line: 3, Column: 35, Message: "'bundle' and 'limit' cannot be used together"},
+		}},
+		{"dependenciesAndValues", `{"files": [{"pattern": "a", "sortOrder": "up", "gpg-key": "key", "archive": "tar"}]}`, SpecValidationErrors{
+			{Line: 1, Column: 29, Message: "'sortOrder' can only be used together with 'sortBy'"},
+			{Line: 1, Column: 48, Message: "'gpg-key' can only be used together with 'bundle'"},
+			{Line: 1, Column: 42, Message: "the value of 'sortOrder' can only be 'asc' or 'desc'"},
+			{Line: 1, Column: 77, Message: "the value of 'archive' can only be 'zip'"},
+		}},
+		{"duplicateKey", `{"files": [{"pattern": "a", "Pattern": "b"}]}`, SpecValidationErrors{
+			{Line: 1, Column: 29, Message: "duplicate key 'Pattern'"},
+		}},
+		{"emptyFiles", `{"files": []}`, SpecValidationErrors{
+			{Line: 1, Column: 11, Message: "spec must include at least one file group"},
+		}},
+		{"syntaxError", "{\"files\": [\n{\"pattern\": \"a\",}\n]}", SpecValidationErrors{
+			{Line: 2, Column: 17, Message: "invalid JSON: invalid character ',' looking for beginning of value"},
+		}},
+		{"unexpectedEnd", `{"files": [`, SpecValidationErrors{
+			{Line: 1, Column: 12, Message: "invalid JSON: unexpected end of JSON input"},
+		}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, ValidateSpecContent([]byte(testCase.content)))
+		})
+	}
+}
+
+func TestCreateSpecFromFileValidationErrors(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "spec.json")
+	assert.NoError(t, os.WriteFile(specFile, []byte("{\"files\": [{\n\"pattern\": \"${REPO}/*\",\n\"recursive\": true\n}]}"), 0600))
+	_, err := CreateSpecFromFile(specFile, map[string]string{"REPO": "generic"})
+	assert.ErrorContains(t, err, "line 3, column 14: 'recursive' must be a string holding a boolean")
+
+	validationErrors, err := ValidateSpecFile(specFile, nil)
+	assert.NoError(t, err)
+	assert.Len(t, validationErrors, 1)
+}
+
+// Verify that the published JSON schema matches the fields known to the validator.
+func TestFileSpecSchema(t *testing.T) {
+	schema := struct {
+		Definitions struct {
+			FileGroup struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"fileGroup"`
+		} `json:"definitions"`
+	}{}
+	assert.NoError(t, json.Unmarshal(FileSpecSchema, &schema))
+	var schemaFields []string
+	for field := range schema.Definitions.FileGroup.Properties {
+		schemaFields = append(schemaFields, field)
+	}
+	sort.Strings(schemaFields)
+	assert.Equal(t, getFileSpecFieldNames(), schemaFields)
+}
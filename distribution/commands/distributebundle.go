@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"time"
+
+	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/formats"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -15,6 +19,8 @@ type DistributeReleaseBundleCommand struct {
 	maxWaitMinutes          int
 	dryRun                  bool
 	autoCreateRepo          bool
+	// When true, the command's result is printed as a formats.DistributionCommandOutput JSON.
+	structuredOutput bool
 }
 
 func NewReleaseBundleDistributeCommand() *DistributeReleaseBundleCommand {
@@ -56,7 +62,26 @@ func (db *DistributeReleaseBundleCommand) SetAutoCreateRepo(autoCreateRepo bool)
 	return db
 }
 
+func (db *DistributeReleaseBundleCommand) SetStructuredOutput(structuredOutput bool) *DistributeReleaseBundleCommand {
+	db.structuredOutput = structuredOutput
+	return db
+}
+
 func (db *DistributeReleaseBundleCommand) Run() error {
+	if !db.structuredOutput {
+		return db.distribute()
+	}
+	startTime := time.Now()
+	err := db.distribute()
+	item := formats.ReleaseBundleOutputItem{Name: db.distributeBundlesParams.Name, Version: db.distributeBundlesParams.Version}
+	output := commandsutils.NewDistributionCommandOutput(db.CommandName(), db.serverDetails.ServerId, time.Since(startTime), []formats.ReleaseBundleOutputItem{item}, err)
+	if e := commandsutils.PrintCommandOutput(output); err == nil {
+		err = e
+	}
+	return err
+}
+
+func (db *DistributeReleaseBundleCommand) distribute() error {
 	servicesManager, err := utils.CreateDistributionServiceManager(db.serverDetails, db.dryRun)
 	if err != nil {
 		return err
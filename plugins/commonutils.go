@@ -1,34 +1,27 @@
 package plugins
 
 import (
-	"errors"
-
-	"github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
-	"github.com/jfrog/jfrog-client-go/utils"
 )
 
 // Get the common 'server-id' flag
+//
+// Deprecated: Use components.GetServerFlags, which includes the 'server-id' flag.
 func GetServerIdFlag() components.StringFlag {
-	return components.StringFlag{
-		Name:        "server-id",
-		Description: "Server ID configured using the config command.",
-	}
+	return components.GetServerIdFlag()
 }
 
 // Return the Artifactory Details of the provided 'server-id', or the default one.
+// Unlike GetServerDetailsByFlags, the initial refreshable tokens of the server are created if needed.
+//
+// Deprecated: Use GetServerDetailsByFlags.
 func GetServerDetails(c *components.Context) (*config.ServerDetails, error) {
-	details, err := commands.GetConfig(c.GetStringFlagValue("server-id"), false)
+	details, err := GetServerDetailsByFlags(c)
 	if err != nil {
 		return nil, err
 	}
-	if details.Url == "" {
-		return nil, errors.New("no server-id was found, or the server-id has no url")
-	}
-	details.Url = utils.AddTrailingSlashIfNeeded(details.Url)
-	err = config.CreateInitialRefreshableTokensIfNeeded(details)
-	if err != nil {
+	if err = config.CreateInitialRefreshableTokensIfNeeded(details); err != nil {
 		return nil, err
 	}
 	return details, nil
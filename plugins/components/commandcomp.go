@@ -1,5 +1,11 @@
 package components
 
+import (
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+)
+
 type Argument struct {
 	Name        string
 	Description string
@@ -14,9 +20,22 @@ type EnvVar struct {
 type ActionFunc func(c *Context) error
 
 type Context struct {
-	Arguments   []string
-	stringFlags map[string]string
-	boolFlags   map[string]bool
+	Arguments        []string
+	stringFlags      map[string]string
+	boolFlags        map[string]bool
+	intFlags         map[string]int
+	stringSliceFlags map[string][]string
+	durationFlags    map[string]time.Duration
+	// The server details resolved by plugins.GetServerDetailsByFlags, or set by the command test harness.
+	serverDetails *config.ServerDetails
+}
+
+func (c *Context) ServerDetails() *config.ServerDetails {
+	return c.serverDetails
+}
+
+func (c *Context) SetServerDetails(serverDetails *config.ServerDetails) {
+	c.serverDetails = serverDetails
 }
 
 func (c *Context) GetStringFlagValue(flagName string) string {
@@ -27,6 +46,23 @@ func (c *Context) GetBoolFlagValue(flagName string) bool {
 	return c.boolFlags[flagName]
 }
 
+func (c *Context) GetIntFlagValue(flagName string) int {
+	return c.intFlags[flagName]
+}
+
+func (c *Context) GetStringSliceFlagValue(flagName string) []string {
+	return c.stringSliceFlags[flagName]
+}
+
+// Returns the value of an EnumFlag. The value is one of the flag's options, or empty if the flag is optional and wasn't provided.
+func (c *Context) GetEnumFlagValue(flagName string) string {
+	return c.stringFlags[flagName]
+}
+
+func (c *Context) GetDurationFlagValue(flagName string) time.Duration {
+	return c.durationFlags[flagName]
+}
+
 type Flag interface {
 	GetName() string
 	GetDescription() string
@@ -69,3 +105,91 @@ func (f BoolFlag) GetDescription() string {
 func (f BoolFlag) GetDefault() bool {
 	return f.DefaultValue
 }
+
+// An integer flag, optionally limited to a range of values.
+type IntFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue int
+	Mandatory    bool
+	// The minimal and maximal allowed values. Nil means no limit.
+	MinValue *int
+	MaxValue *int
+}
+
+func (f IntFlag) GetName() string {
+	return f.Name
+}
+
+func (f IntFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f IntFlag) GetDefault() int {
+	return f.DefaultValue
+}
+
+// A flag receiving a comma-separated list of values.
+type StringSliceFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue []string
+	Mandatory    bool
+}
+
+func (f StringSliceFlag) GetName() string {
+	return f.Name
+}
+
+func (f StringSliceFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f StringSliceFlag) GetDefault() []string {
+	return f.DefaultValue
+}
+
+// A flag receiving one of a predefined list of values.
+type EnumFlag struct {
+	Name        string
+	Description string
+	Options     []string
+	// A flag with default value cannot be mandatory. The default value must be one of the options.
+	DefaultValue string
+	Mandatory    bool
+}
+
+func (f EnumFlag) GetName() string {
+	return f.Name
+}
+
+func (f EnumFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f EnumFlag) GetDefault() string {
+	return f.DefaultValue
+}
+
+// A flag receiving a duration, such as '90s' or '1h30m'.
+type DurationFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue time.Duration
+	Mandatory    bool
+}
+
+func (f DurationFlag) GetName() string {
+	return f.Name
+}
+
+func (f DurationFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f DurationFlag) GetDefault() time.Duration {
+	return f.DefaultValue
+}
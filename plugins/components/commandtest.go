@@ -0,0 +1,109 @@
+package components
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/urfave/cli"
+)
+
+// CommandTest runs a plugin command's action in-process, for unit-testing the command without building and running the plugin.
+// The arguments and flags are parsed and validated the same way as when running the plugin, so default values,
+// mandatory flags and typed flags behave as they do in the CLI.
+type CommandTest struct {
+	command       Command
+	args          []string
+	flags         []string
+	serverDetails *config.ServerDetails
+}
+
+// The result of running a command using CommandTest.
+type CommandTestResult struct {
+	// The context the command's action was called with. Nil if the arguments or flags are invalid.
+	Context *Context
+	// Text written by log.Output.
+	Output string
+	// The log messages.
+	Logs string
+	// The error returned by the command's action, or the arguments and flags parsing error.
+	Err error
+}
+
+func NewCommandTest(cmd Command) *CommandTest {
+	return &CommandTest{command: cmd}
+}
+
+func (ct *CommandTest) SetArgs(args ...string) *CommandTest {
+	ct.args = args
+	return ct
+}
+
+// Sets the value of a flag, as if provided in the command line as --<name>=<value>.
+func (ct *CommandTest) SetFlag(name, value string) *CommandTest {
+	ct.flags = append(ct.flags, "--"+name+"="+value)
+	return ct
+}
+
+func (ct *CommandTest) SetBoolFlag(name string, value bool) *CommandTest {
+	return ct.SetFlag(name, strconv.FormatBool(value))
+}
+
+// Sets the server details returned by plugins.GetServerDetailsByFlags, instead of resolving them from the server flags and the configuration.
+func (ct *CommandTest) SetServerDetails(serverDetails *config.ServerDetails) *CommandTest {
+	ct.serverDetails = serverDetails
+	return ct
+}
+
+// Creates the context the command's action is called with, without running the action.
+// Useful for testing functions which receive a context.
+func (ct *CommandTest) CreateContext() (*Context, error) {
+	convertedFlags, err := convertFlags(ct.command)
+	if err != nil {
+		return nil, err
+	}
+	flagSet := flag.NewFlagSet(ct.command.Name, flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	for _, convertedFlag := range convertedFlags {
+		convertedFlag.Apply(flagSet)
+	}
+	if err = flagSet.Parse(append(append([]string{}, ct.flags...), ct.args...)); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	baseContext := cli.NewContext(nil, flagSet, nil)
+	pluginContext := &Context{Arguments: baseContext.Args(), serverDetails: ct.serverDetails}
+	if err = fillFlagMaps(pluginContext, baseContext, ct.command.Flags); err != nil {
+		return nil, err
+	}
+	return pluginContext, nil
+}
+
+// Runs the command's action, while capturing the output and the logs.
+// The global logger is replaced during the run, therefore command tests should not run in parallel.
+func (ct *CommandTest) Run() *CommandTestResult {
+	result := new(CommandTestResult)
+	if ct.command.Action == nil {
+		result.Err = errorutils.CheckErrorf("command '%s' has no action", ct.command.Name)
+		return result
+	}
+	if result.Context, result.Err = ct.CreateContext(); result.Err != nil {
+		return result
+	}
+	outputBuffer, logsBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+	previousLog := log.Logger
+	testLog := log.NewLogger(corelog.GetCliLogLevel(), nil)
+	testLog.SetOutputWriter(outputBuffer)
+	testLog.SetLogsWriter(logsBuffer, 0)
+	log.SetLogger(testLog)
+	defer func() {
+		log.SetLogger(previousLog)
+		result.Output, result.Logs = outputBuffer.String(), logsBuffer.String()
+	}()
+	result.Err = ct.command.Action(result.Context)
+	return result
+}
@@ -0,0 +1,78 @@
+package components
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+var greetCommand = Command{
+	Name: "greet",
+	Flags: []Flag{
+		StringFlag{Name: "greeting", DefaultValue: "Hello"},
+		BoolFlag{Name: "shout"},
+		IntFlag{Name: "times", DefaultValue: 1},
+		DurationFlag{Name: "delay"},
+	},
+	Action: func(c *Context) error {
+		if len(c.Arguments) != 1 {
+			return errors.New("wrong number of arguments")
+		}
+		log.Info("Greeting", c.Arguments[0])
+		greeting := c.GetStringFlagValue("greeting") + " " + c.Arguments[0]
+		if c.GetBoolFlagValue("shout") {
+			greeting += "!"
+		}
+		for i := 0; i < c.GetIntFlagValue("times"); i++ {
+			log.Output(greeting)
+		}
+		return nil
+	},
+}
+
+func TestCommandTestRun(t *testing.T) {
+	result := NewCommandTest(greetCommand).SetArgs("world").Run()
+	assert.NoError(t, result.Err)
+	assert.Equal(t, "Hello world\n", result.Output)
+	assert.Contains(t, result.Logs, "Greeting world")
+
+	result = NewCommandTest(greetCommand).SetArgs("world").SetFlag("greeting", "Hi").SetBoolFlag("shout", true).SetFlag("times", "2").Run()
+	assert.NoError(t, result.Err)
+	assert.Equal(t, "Hi world!\nHi world!\n", result.Output)
+
+	// Errors returned by the action
+	result = NewCommandTest(greetCommand).Run()
+	assert.EqualError(t, result.Err, "wrong number of arguments")
+	assert.NotNil(t, result.Context)
+
+	// Invalid flags values fail before running the action
+	result = NewCommandTest(greetCommand).SetArgs("world").SetFlag("times", "many").Run()
+	assert.Error(t, result.Err)
+	assert.Nil(t, result.Context)
+	assert.Empty(t, result.Output)
+	result = NewCommandTest(greetCommand).SetFlag("unknown", "value").Run()
+	assert.Error(t, result.Err)
+
+	// Commands without an action
+	assert.Error(t, NewCommandTest(Command{Name: "group"}).Run().Err)
+}
+
+func TestCommandTestCreateContext(t *testing.T) {
+	serverDetails := &config.ServerDetails{ServerId: "test", Url: "https://test.jfrog.io/"}
+	context, err := NewCommandTest(greetCommand).SetArgs("a", "b").SetFlag("delay", "5s").SetServerDetails(serverDetails).CreateContext()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, context.Arguments)
+	assert.Equal(t, "Hello", context.GetStringFlagValue("greeting"))
+	assert.False(t, context.GetBoolFlagValue("shout"))
+	assert.Equal(t, 1, context.GetIntFlagValue("times"))
+	assert.Equal(t, 5*time.Second, context.GetDurationFlagValue("delay"))
+	assert.Equal(t, serverDetails, context.ServerDetails())
+
+	// Mandatory flags
+	_, err = NewCommandTest(Command{Name: "mandatory", Flags: []Flag{StringFlag{Name: "env", Mandatory: true}}}).CreateContext()
+	assert.Error(t, err)
+}
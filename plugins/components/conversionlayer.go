@@ -6,7 +6,9 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/docs/common"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/urfave/cli"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func ConvertApp(jfrogApp App) (*cli.App, error) {
@@ -25,9 +27,22 @@ func ConvertApp(jfrogApp App) (*cli.App, error) {
 }
 
 func convertCommands(jfrogApp App) ([]cli.Command, error) {
+	return convertCommandsList(jfrogApp.Commands, jfrogApp.Name)
+}
+
+// Converts the commands, and their subcommands, recursively.
+// namePath is the path of the commands' parent, starting with the app name, such as 'app group'.
+func convertCommandsList(commands []Command, namePath string) ([]cli.Command, error) {
 	var converted []cli.Command
-	for _, cmd := range jfrogApp.Commands {
-		cur, err := convertCommand(cmd, jfrogApp.Name)
+	names := make(map[string]bool)
+	for _, cmd := range commands {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if names[name] {
+				return converted, fmt.Errorf("'%s' has more than one command named '%s'", namePath, name)
+			}
+			names[name] = true
+		}
+		cur, err := convertCommand(cmd, namePath)
 		if err != nil {
 			return converted, err
 		}
@@ -41,6 +56,9 @@ func convertCommand(cmd Command, appName string) (cli.Command, error) {
 	if err != nil {
 		return cli.Command{}, err
 	}
+	if len(cmd.Subcommands) > 0 {
+		return convertCommandGroup(cmd, appName, convertedFlags)
+	}
 	return cli.Command{
 		Name:            cmd.Name,
 		Flags:           convertedFlags,
@@ -56,8 +74,43 @@ func convertCommand(cmd Command, appName string) (cli.Command, error) {
 	}, nil
 }
 
+// Converts a command with subcommands. Running the command without a subcommand runs its Action if defined, or shows its help otherwise.
+func convertCommandGroup(cmd Command, namePath string, convertedFlags []cli.Flag) (cli.Command, error) {
+	groupPath := namePath + " " + cmd.Name
+	subcommands, err := convertCommandsList(cmd.Subcommands, groupPath)
+	if err != nil {
+		return cli.Command{}, err
+	}
+	var subcommandNames []string
+	for _, subcommand := range cmd.Subcommands {
+		subcommandNames = append(subcommandNames, subcommand.Name)
+	}
+	group := cli.Command{
+		Name:        cmd.Name,
+		Flags:       convertedFlags,
+		Aliases:     cmd.Aliases,
+		Description: cmd.Description,
+		Usage:       cmd.Description,
+		HelpName:    groupPath,
+		Subcommands: subcommands,
+		// Complete the subcommands names, in addition to the group's flags.
+		BashComplete:    common.CreateBashCompletionFunc(subcommandNames...),
+		SkipFlagParsing: cmd.SkipFlagParsing,
+	}
+	if cmd.Action != nil {
+		group.Action = getActionFunc(cmd)
+	}
+	return group, nil
+}
+
 func createCommandUsage(cmd Command, appName string) string {
 	usage := fmt.Sprintf(coreutils.GetCliExecutableName()+" %s %s", appName, cmd.Name)
+	// Flags which must be provided by the user are shown explicitly, since the command can't run without them.
+	for _, flag := range cmd.Flags {
+		if isMandatoryFlag(flag) {
+			usage += fmt.Sprintf(" --%s=%s", flag.GetName(), getFlagValuePlaceholder(flag))
+		}
+	}
 	if len(cmd.Flags) > 0 {
 		usage += " [command options]"
 	}
@@ -95,9 +148,48 @@ func createEnvVarsSummary(cmd Command) string {
 	return strings.Join(envVarsSummary[:], "\n\n")
 }
 
+// Returns true if the flag must be provided by the user.
+func isMandatoryFlag(flag Flag) bool {
+	switch f := flag.(type) {
+	case StringFlag:
+		return f.Mandatory && f.DefaultValue == ""
+	case IntFlag:
+		return f.Mandatory && f.DefaultValue == 0
+	case StringSliceFlag:
+		return f.Mandatory && len(f.DefaultValue) == 0
+	case EnumFlag:
+		return f.Mandatory && f.DefaultValue == ""
+	case DurationFlag:
+		return f.Mandatory && f.DefaultValue == 0
+	}
+	return false
+}
+
+func getFlagValuePlaceholder(flag Flag) string {
+	switch f := flag.(type) {
+	case IntFlag:
+		return "<int>"
+	case StringSliceFlag:
+		return "<value1,value2,...>"
+	case EnumFlag:
+		return "<" + strings.Join(f.Options, "|") + ">"
+	case DurationFlag:
+		return "<duration>"
+	}
+	return "<value>"
+}
+
 func convertFlags(cmd Command) ([]cli.Flag, error) {
 	var convertedFlags []cli.Flag
+	flagNames := make(map[string]bool)
 	for _, flag := range cmd.Flags {
+		if flagNames[flag.GetName()] {
+			return convertedFlags, fmt.Errorf("command '%s' has more than one flag named '%s'", cmd.Name, flag.GetName())
+		}
+		flagNames[flag.GetName()] = true
+		if err := validateFlag(flag); err != nil {
+			return convertedFlags, err
+		}
 		converted, err := convertByType(flag)
 		if err != nil {
 			return convertedFlags, err
@@ -109,33 +201,120 @@ func convertFlags(cmd Command) ([]cli.Flag, error) {
 	return convertedFlags, nil
 }
 
+// Verifies that the flag's definition is consistent, so that errors in the plugin are found before the command runs.
+func validateFlag(flag Flag) error {
+	if flag.GetName() == "" {
+		return errors.New("flags must have a name")
+	}
+	switch f := flag.(type) {
+	case IntFlag:
+		if f.MinValue != nil && f.MaxValue != nil && *f.MinValue > *f.MaxValue {
+			return fmt.Errorf("flag '%s' has a minimal value greater than its maximal value", f.Name)
+		}
+		// An optional flag with no default value receives 0 if not provided, so 0 must be in range.
+		if f.DefaultValue != 0 || !f.Mandatory {
+			return validateIntFlagRange(f, f.DefaultValue)
+		}
+	case EnumFlag:
+		if len(f.Options) == 0 {
+			return fmt.Errorf("flag '%s' has no options", f.Name)
+		}
+		if f.DefaultValue != "" {
+			return validateEnumFlagOption(f, f.DefaultValue)
+		}
+	case DurationFlag:
+		if f.DefaultValue < 0 {
+			return fmt.Errorf("flag '%s' has a negative default value", f.Name)
+		}
+	}
+	return nil
+}
+
 func convertByType(flag Flag) (cli.Flag, error) {
-	if f, ok := flag.(StringFlag); ok {
+	switch f := flag.(type) {
+	case StringFlag:
 		return convertStringFlag(f), nil
-	}
-	if f, ok := flag.(BoolFlag); ok {
+	case BoolFlag:
 		return convertBoolFlag(f), nil
+	case IntFlag:
+		return convertIntFlag(f), nil
+	case StringSliceFlag:
+		return convertStringSliceFlag(f), nil
+	case EnumFlag:
+		return convertEnumFlag(f), nil
+	case DurationFlag:
+		return convertDurationFlag(f), nil
 	}
 	return nil, fmt.Errorf("flag '%s' does not match any known flag type", flag.GetName())
 }
 
+// Creates the usage of a flag receiving a value. The default value is shown if set, otherwise the flag is marked as mandatory/optional.
+// The details, such as the allowed values, are shown after the default value.
+func createValueFlagUsage(description, defaultValue string, mandatory bool, details ...string) string {
+	var usage string
+	switch {
+	case defaultValue != "":
+		usage = fmt.Sprintf("[Default: %s] ", defaultValue)
+	case mandatory:
+		usage = "[Mandatory] "
+	default:
+		usage = "[Optional] "
+	}
+	for _, detail := range details {
+		usage += "[" + detail + "] "
+	}
+	return usage + description + "` `"
+}
+
 func convertStringFlag(f StringFlag) cli.Flag {
-	stringFlag := cli.StringFlag{
+	return cli.StringFlag{
 		Name:  f.Name,
-		Usage: f.Description + "` `",
+		Usage: createValueFlagUsage(f.Description, f.DefaultValue, f.Mandatory),
 	}
-	// If default is set, add its value and return.
-	if f.DefaultValue != "" {
-		stringFlag.Usage = fmt.Sprintf("[Default: %s] %s", f.DefaultValue, stringFlag.Usage)
-		return stringFlag
+}
+
+// Typed flags are received as strings, and parsed when filling the context, to allow clear error messages.
+func convertIntFlag(f IntFlag) cli.Flag {
+	defaultValue := ""
+	if f.DefaultValue != 0 {
+		defaultValue = strconv.Itoa(f.DefaultValue)
 	}
-	// Otherwise, mark as mandatory/optional accordingly.
-	if f.Mandatory {
-		stringFlag.Usage = "[Mandatory] " + stringFlag.Usage
-	} else {
-		stringFlag.Usage = "[Optional] " + stringFlag.Usage
+	var details []string
+	if f.MinValue != nil {
+		details = append(details, "Min: "+strconv.Itoa(*f.MinValue))
+	}
+	if f.MaxValue != nil {
+		details = append(details, "Max: "+strconv.Itoa(*f.MaxValue))
+	}
+	return cli.StringFlag{
+		Name:  f.Name,
+		Usage: createValueFlagUsage(f.Description, defaultValue, f.Mandatory, details...),
+	}
+}
+
+func convertStringSliceFlag(f StringSliceFlag) cli.Flag {
+	return cli.StringFlag{
+		Name:  f.Name,
+		Usage: createValueFlagUsage(f.Description, strings.Join(f.DefaultValue, ","), f.Mandatory, "Comma-separated list"),
+	}
+}
+
+func convertEnumFlag(f EnumFlag) cli.Flag {
+	return cli.StringFlag{
+		Name:  f.Name,
+		Usage: createValueFlagUsage(f.Description, f.DefaultValue, f.Mandatory, "Options: "+strings.Join(f.Options, ", ")),
+	}
+}
+
+func convertDurationFlag(f DurationFlag) cli.Flag {
+	defaultValue := ""
+	if f.DefaultValue != 0 {
+		defaultValue = f.DefaultValue.String()
+	}
+	return cli.StringFlag{
+		Name:  f.Name,
+		Usage: createValueFlagUsage(f.Description, defaultValue, f.Mandatory, "Duration, such as 90s or 1h30m"),
 	}
-	return stringFlag
 }
 
 func convertBoolFlag(f BoolFlag) cli.Flag {
@@ -167,20 +346,29 @@ func getActionFunc(cmd Command) cli.ActionFunc {
 func fillFlagMaps(c *Context, baseContext *cli.Context, originalFlags []Flag) error {
 	c.stringFlags = make(map[string]string)
 	c.boolFlags = make(map[string]bool)
+	c.intFlags = make(map[string]int)
+	c.stringSliceFlags = make(map[string][]string)
+	c.durationFlags = make(map[string]time.Duration)
 
 	// Loop over all plugin's known flags.
 	for _, flag := range originalFlags {
-		if stringFlag, ok := flag.(StringFlag); ok {
-			finalValue, err := getValueForStringFlag(stringFlag, baseContext.String(stringFlag.Name))
-			if err != nil {
-				return err
-			}
-			c.stringFlags[stringFlag.Name] = finalValue
-			continue
+		var err error
+		switch f := flag.(type) {
+		case StringFlag:
+			c.stringFlags[f.Name], err = getValueForStringFlag(f, baseContext.String(f.Name))
+		case BoolFlag:
+			c.boolFlags[f.Name] = getValueForBoolFlag(f, baseContext)
+		case IntFlag:
+			c.intFlags[f.Name], err = getValueForIntFlag(f, baseContext.String(f.Name))
+		case StringSliceFlag:
+			c.stringSliceFlags[f.Name], err = getValueForStringSliceFlag(f, baseContext.String(f.Name))
+		case EnumFlag:
+			c.stringFlags[f.Name], err = getValueForEnumFlag(f, baseContext.String(f.Name))
+		case DurationFlag:
+			c.durationFlags[f.Name], err = getValueForDurationFlag(f, baseContext.String(f.Name))
 		}
-
-		if boolFlag, ok := flag.(BoolFlag); ok {
-			c.boolFlags[boolFlag.Name] = getValueForBoolFlag(boolFlag, baseContext)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -207,3 +395,79 @@ func getValueForBoolFlag(f BoolFlag, baseContext *cli.Context) bool {
 	}
 	return baseContext.Bool(f.Name)
 }
+
+func getValueForIntFlag(f IntFlag, receivedValue string) (int, error) {
+	if receivedValue == "" {
+		if f.DefaultValue == 0 && f.Mandatory {
+			return 0, errors.New("Mandatory flag '" + f.Name + "' is missing")
+		}
+		return f.DefaultValue, nil
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(receivedValue))
+	if err != nil {
+		return 0, fmt.Errorf("flag '%s' expects an integer, but received '%s'", f.Name, receivedValue)
+	}
+	return value, validateIntFlagRange(f, value)
+}
+
+func validateIntFlagRange(f IntFlag, value int) error {
+	if f.MinValue != nil && value < *f.MinValue {
+		return fmt.Errorf("flag '%s' value must be at least %d, but received %d", f.Name, *f.MinValue, value)
+	}
+	if f.MaxValue != nil && value > *f.MaxValue {
+		return fmt.Errorf("flag '%s' value must be at most %d, but received %d", f.Name, *f.MaxValue, value)
+	}
+	return nil
+}
+
+func getValueForStringSliceFlag(f StringSliceFlag, receivedValue string) ([]string, error) {
+	var values []string
+	for _, value := range strings.Split(receivedValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 {
+		return values, nil
+	}
+	if len(f.DefaultValue) == 0 && f.Mandatory {
+		return nil, errors.New("Mandatory flag '" + f.Name + "' is missing")
+	}
+	return f.DefaultValue, nil
+}
+
+func getValueForEnumFlag(f EnumFlag, receivedValue string) (string, error) {
+	if receivedValue == "" {
+		if f.DefaultValue == "" && f.Mandatory {
+			return "", errors.New("Mandatory flag '" + f.Name + "' is missing")
+		}
+		return f.DefaultValue, nil
+	}
+	return receivedValue, validateEnumFlagOption(f, receivedValue)
+}
+
+func validateEnumFlagOption(f EnumFlag, value string) error {
+	for _, option := range f.Options {
+		if option == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("flag '%s' value must be one of: %s, but received '%s'", f.Name, strings.Join(f.Options, ", "), value)
+}
+
+func getValueForDurationFlag(f DurationFlag, receivedValue string) (time.Duration, error) {
+	if receivedValue == "" {
+		if f.DefaultValue == 0 && f.Mandatory {
+			return 0, errors.New("Mandatory flag '" + f.Name + "' is missing")
+		}
+		return f.DefaultValue, nil
+	}
+	value, err := time.ParseDuration(strings.TrimSpace(receivedValue))
+	if err != nil {
+		return 0, fmt.Errorf("flag '%s' expects a duration, such as 90s or 1h30m, but received '%s'", f.Name, receivedValue)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("flag '%s' expects a non-negative duration, but received '%s'", f.Name, receivedValue)
+	}
+	return value, nil
+}
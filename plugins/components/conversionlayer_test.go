@@ -5,6 +5,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestCreateCommandUsage(t *testing.T) {
@@ -214,3 +215,209 @@ func TestGetValueForStringFlag(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, finalValue, expected)
 }
+
+func TestCreateCommandUsageWithMandatoryFlags(t *testing.T) {
+	cmd := Command{
+		Name: "test-command",
+		Flags: []Flag{
+			StringFlag{Name: "optional"},
+			StringFlag{Name: "mandatory", Mandatory: true},
+			EnumFlag{Name: "mode", Options: []string{"fast", "safe"}, Mandatory: true},
+			IntFlag{Name: "threads", Mandatory: true, DefaultValue: 3},
+		},
+	}
+	appName := "test-app"
+	expected := fmt.Sprintf("%s %s %s --mandatory=<value> --mode=<fast|safe> [command options]", coreutils.GetCliExecutableName(), appName, cmd.Name)
+	assert.Equal(t, expected, createCommandUsage(cmd, appName))
+}
+
+func TestConvertTypedFlags(t *testing.T) {
+	minValue, maxValue := 1, 10
+	testCases := []struct {
+		flag     Flag
+		expected string
+	}{
+		{IntFlag{Name: "int-flag", Description: "This is how you use it.", DefaultValue: 3, MinValue: &minValue, MaxValue: &maxValue}, "--int-flag  \t[Default: 3] [Min: 1] [Max: 10] This is how you use it."},
+		{IntFlag{Name: "int-flag", Description: "This is how you use it.", Mandatory: true}, "--int-flag  \t[Mandatory] This is how you use it."},
+		{StringSliceFlag{Name: "slice-flag", Description: "This is how you use it.", DefaultValue: []string{"a", "b"}}, "--slice-flag  \t[Default: a,b] [Comma-separated list] This is how you use it."},
+		{EnumFlag{Name: "enum-flag", Description: "This is how you use it.", Options: []string{"a", "b"}}, "--enum-flag  \t[Optional] [Options: a, b] This is how you use it."},
+		{DurationFlag{Name: "duration-flag", Description: "This is how you use it.", DefaultValue: 90 * time.Second}, "--duration-flag  \t[Default: 1m30s] [Duration, such as 90s or 1h30m] This is how you use it."},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expected, func(t *testing.T) {
+			converted, err := convertByType(testCase.flag)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, converted.String())
+		})
+	}
+}
+
+func TestConvertFlagsValidation(t *testing.T) {
+	minValue, maxValue := 5, 1
+	testCases := []struct {
+		name  string
+		flags []Flag
+	}{
+		{"duplicateNames", []Flag{StringFlag{Name: "flag"}, BoolFlag{Name: "flag"}}},
+		{"missingName", []Flag{StringFlag{}}},
+		{"minGreaterThanMax", []Flag{IntFlag{Name: "flag", MinValue: &minValue, MaxValue: &maxValue}}},
+		{"defaultOutOfRange", []Flag{IntFlag{Name: "flag", DefaultValue: 1, MinValue: &minValue}}},
+		{"optionalWithoutDefaultOutOfRange", []Flag{IntFlag{Name: "flag", MinValue: &minValue}}},
+		{"noOptions", []Flag{EnumFlag{Name: "flag"}}},
+		{"defaultNotAnOption", []Flag{EnumFlag{Name: "flag", Options: []string{"a"}, DefaultValue: "b"}}},
+		{"negativeDuration", []Flag{DurationFlag{Name: "flag", DefaultValue: -time.Second}}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := convertFlags(Command{Name: "cmd", Flags: testCase.flags})
+			assert.Error(t, err)
+		})
+	}
+	// A mandatory flag with no default value is always provided, so its range isn't checked against 0.
+	_, err := convertFlags(Command{Name: "cmd", Flags: []Flag{IntFlag{Name: "flag", Mandatory: true, MinValue: &minValue}}})
+	assert.NoError(t, err)
+}
+
+func TestGetValueForTypedFlags(t *testing.T) {
+	minValue, maxValue := 1, 10
+	intFlag := IntFlag{Name: "int-flag", DefaultValue: 3, MinValue: &minValue, MaxValue: &maxValue}
+	intValue, err := getValueForIntFlag(intFlag, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, intValue)
+	intValue, err = getValueForIntFlag(intFlag, "7")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, intValue)
+	for _, invalid := range []string{"0", "11", "seven"} {
+		_, err = getValueForIntFlag(intFlag, invalid)
+		assert.Error(t, err, invalid)
+	}
+	_, err = getValueForIntFlag(IntFlag{Name: "int-flag", Mandatory: true}, "")
+	assert.Error(t, err)
+
+	sliceFlag := StringSliceFlag{Name: "slice-flag", DefaultValue: []string{"default"}}
+	sliceValue, err := getValueForStringSliceFlag(sliceFlag, "a, b,,c ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, sliceValue)
+	sliceValue, err = getValueForStringSliceFlag(sliceFlag, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default"}, sliceValue)
+	_, err = getValueForStringSliceFlag(StringSliceFlag{Name: "slice-flag", Mandatory: true}, " , ")
+	assert.Error(t, err)
+
+	enumFlag := EnumFlag{Name: "enum-flag", Options: []string{"a", "b"}, DefaultValue: "a"}
+	enumValue, err := getValueForEnumFlag(enumFlag, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", enumValue)
+	enumValue, err = getValueForEnumFlag(enumFlag, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", enumValue)
+	_, err = getValueForEnumFlag(enumFlag, "c")
+	assert.Error(t, err)
+
+	durationFlag := DurationFlag{Name: "duration-flag", DefaultValue: time.Minute}
+	durationValue, err := getValueForDurationFlag(durationFlag, "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, durationValue)
+	durationValue, err = getValueForDurationFlag(durationFlag, "1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, durationValue)
+	for _, invalid := range []string{"10", "-1s"} {
+		_, err = getValueForDurationFlag(durationFlag, invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTypedFlagsContext(t *testing.T) {
+	var actualContext *Context
+	app, err := ConvertApp(App{
+		Name: "test-app",
+		Commands: []Command{{
+			Name: "test-command",
+			Flags: []Flag{
+				IntFlag{Name: "threads", DefaultValue: 3},
+				StringSliceFlag{Name: "repos"},
+				EnumFlag{Name: "mode", Options: []string{"fast", "safe"}, DefaultValue: "safe"},
+				DurationFlag{Name: "timeout"},
+			},
+			Action: func(c *Context) error {
+				actualContext = c
+				return nil
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, app.Run([]string{"test-app", "test-command", "--repos=a,b", "--mode=fast", "--timeout=2m", "arg"}))
+	if assert.NotNil(t, actualContext) {
+		assert.Equal(t, 3, actualContext.GetIntFlagValue("threads"))
+		assert.Equal(t, []string{"a", "b"}, actualContext.GetStringSliceFlagValue("repos"))
+		assert.Equal(t, "fast", actualContext.GetEnumFlagValue("mode"))
+		assert.Equal(t, 2*time.Minute, actualContext.GetDurationFlagValue("timeout"))
+		assert.Equal(t, []string{"arg"}, actualContext.Arguments)
+	}
+	assert.Error(t, app.Run([]string{"test-app", "test-command", "--mode=slow"}))
+}
+
+func TestConvertSubcommands(t *testing.T) {
+	var ranCommand string
+	newAction := func(name string) ActionFunc {
+		return func(c *Context) error {
+			ranCommand = name + ":" + c.GetStringFlagValue("target")
+			return nil
+		}
+	}
+	app, err := ConvertApp(App{
+		Name: "test-app",
+		Commands: []Command{
+			{Name: "flat", Action: newAction("flat")},
+			{
+				Name:        "repo",
+				Description: "Repositories commands.",
+				Subcommands: []Command{
+					{Name: "sync", Flags: []Flag{StringFlag{Name: "target"}}, Action: newAction("sync")},
+					{Name: "replication", Subcommands: []Command{{Name: "create", Aliases: []string{"c"}, Action: newAction("create")}}},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, app.Commands, 2) {
+		return
+	}
+	repoCommand := app.Commands[1]
+	assert.Equal(t, "test-app repo", repoCommand.HelpName)
+	assert.Equal(t, "Repositories commands.", repoCommand.Usage)
+	if assert.Len(t, repoCommand.Subcommands, 2) {
+		assert.Contains(t, repoCommand.Subcommands[0].HelpName, fmt.Sprintf("%s test-app repo sync [command options]", coreutils.GetCliExecutableName()))
+		assert.Len(t, repoCommand.Subcommands[1].Subcommands, 1)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"flat"}, "flat:"},
+		{[]string{"repo", "sync", "--target=repo-1"}, "sync:repo-1"},
+		{[]string{"repo", "replication", "create"}, "create:"},
+		{[]string{"repo", "replication", "c"}, "create:"},
+	}
+	for _, testCase := range testCases {
+		ranCommand = ""
+		assert.NoError(t, app.Run(append([]string{"test-app"}, testCase.args...)))
+		assert.Equal(t, testCase.expected, ranCommand)
+	}
+}
+
+func TestConvertSubcommandsDuplicateNames(t *testing.T) {
+	action := func(c *Context) error { return nil }
+	_, err := ConvertApp(App{
+		Name: "test-app",
+		Commands: []Command{{
+			Name: "repo",
+			Subcommands: []Command{
+				{Name: "sync", Action: action},
+				{Name: "audit", Aliases: []string{"sync"}, Action: action},
+			},
+		}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Generates a JSON reference document of the plugin's commands, arguments, flags and environment variables.
+// The document has the same structure as the plugin's signature.
+func GenerateJsonDocs(jfrogApp App) ([]byte, error) {
+	content, err := json.MarshalIndent(CreatePluginSignature(jfrogApp, ""), "", "  ")
+	return content, errorutils.CheckError(err)
+}
+
+// Generates a Markdown reference document of the plugin's commands, arguments, flags and environment variables.
+func GenerateMarkdownDocs(jfrogApp App) string {
+	var docs strings.Builder
+	docs.WriteString("# " + jfrogApp.Name + "\n\n")
+	if jfrogApp.Description != "" {
+		docs.WriteString(jfrogApp.Description + "\n\n")
+	}
+	if jfrogApp.Version != "" {
+		docs.WriteString("**Version:** " + jfrogApp.Version + "\n\n")
+	}
+	if compatibility := createCoreVersionsSummary(jfrogApp); compatibility != "" {
+		docs.WriteString("**Supported JFrog CLI core versions:** " + compatibility + "\n\n")
+	}
+	if len(jfrogApp.Commands) > 0 {
+		docs.WriteString("## Commands\n\n")
+		writeCommandsMarkdown(&docs, jfrogApp.Commands, jfrogApp.Name)
+	}
+	return strings.TrimSuffix(docs.String(), "\n")
+}
+
+func createCoreVersionsSummary(jfrogApp App) string {
+	switch {
+	case jfrogApp.MinCoreVersion != "" && jfrogApp.MaxCoreVersion != "":
+		return jfrogApp.MinCoreVersion + " - " + jfrogApp.MaxCoreVersion
+	case jfrogApp.MinCoreVersion != "":
+		return jfrogApp.MinCoreVersion + " or higher"
+	case jfrogApp.MaxCoreVersion != "":
+		return "up to " + jfrogApp.MaxCoreVersion
+	}
+	return ""
+}
+
+func writeCommandsMarkdown(docs *strings.Builder, commands []Command, namePath string) {
+	for _, cmd := range commands {
+		docs.WriteString("### " + namePath + " " + cmd.Name + "\n\n")
+		if cmd.Description != "" {
+			docs.WriteString(cmd.Description + "\n\n")
+		}
+		if len(cmd.Subcommands) == 0 || cmd.Action != nil {
+			docs.WriteString("**Usage:** `" + createCommandUsage(cmd, namePath) + "`\n\n")
+		}
+		if len(cmd.Aliases) > 0 {
+			docs.WriteString("**Aliases:** `" + strings.Join(cmd.Aliases, "`, `") + "`\n\n")
+		}
+		writeArgumentsMarkdown(docs, cmd.Arguments)
+		writeFlagsMarkdown(docs, cmd.Flags)
+		writeEnvVarsMarkdown(docs, cmd.EnvVars)
+		writeCommandsMarkdown(docs, cmd.Subcommands, namePath+" "+cmd.Name)
+	}
+}
+
+func writeArgumentsMarkdown(docs *strings.Builder, arguments []Argument) {
+	if len(arguments) == 0 {
+		return
+	}
+	docs.WriteString("#### Arguments\n\n| Name | Description |\n| --- | --- |\n")
+	for _, argument := range arguments {
+		writeMarkdownTableRow(docs, argument.Name, argument.Description)
+	}
+	docs.WriteString("\n")
+}
+
+func writeFlagsMarkdown(docs *strings.Builder, flags []Flag) {
+	if len(flags) == 0 {
+		return
+	}
+	docs.WriteString("#### Flags\n\n| Name | Type | Default | Mandatory | Description |\n| --- | --- | --- | --- | --- |\n")
+	for _, flag := range flags {
+		signature := createFlagSignature(flag)
+		mandatory := "No"
+		if signature.Mandatory {
+			mandatory = "Yes"
+		}
+		writeMarkdownTableRow(docs, "`--"+signature.Name+"`", createFlagTypeSummary(signature), signature.DefaultValue, mandatory, signature.Description)
+	}
+	docs.WriteString("\n")
+}
+
+// Returns the flag's type, including the allowed values if limited.
+func createFlagTypeSummary(signature FlagSignature) string {
+	summary := signature.Type
+	switch {
+	case len(signature.Options) > 0:
+		summary += fmt.Sprintf(" (%s)", strings.Join(signature.Options, ", "))
+	case signature.MinValue != nil && signature.MaxValue != nil:
+		summary += fmt.Sprintf(" (%d - %d)", *signature.MinValue, *signature.MaxValue)
+	case signature.MinValue != nil:
+		summary += fmt.Sprintf(" (min %d)", *signature.MinValue)
+	case signature.MaxValue != nil:
+		summary += fmt.Sprintf(" (max %d)", *signature.MaxValue)
+	}
+	return summary
+}
+
+func writeEnvVarsMarkdown(docs *strings.Builder, envVars []EnvVar) {
+	if len(envVars) == 0 {
+		return
+	}
+	docs.WriteString("#### Environment Variables\n\n| Name | Default | Description |\n| --- | --- | --- |\n")
+	for _, envVar := range envVars {
+		writeMarkdownTableRow(docs, "`"+envVar.Name+"`", envVar.Default, envVar.Description)
+	}
+	docs.WriteString("\n")
+}
+
+func writeMarkdownTableRow(docs *strings.Builder, cells ...string) {
+	for i, cell := range cells {
+		// Pipes and line breaks would break the table's structure.
+		cells[i] = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>").Replace(cell)
+	}
+	docs.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
@@ -0,0 +1,71 @@
+package components
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var docsTestApp = App{
+	Name:           "docs-plugin",
+	Description:    "Docs plugin.",
+	Version:        "1.0.0",
+	MinCoreVersion: "2.0.0",
+	Commands: []Command{
+		{
+			Name:        "deploy",
+			Description: "Deploy the application.",
+			Aliases:     []string{"d"},
+			Arguments:   []Argument{{Name: "target", Description: "The deployment target."}},
+			Flags: []Flag{
+				StringFlag{Name: "env", Description: "Environment | stage.", Mandatory: true},
+				EnumFlag{Name: "mode", Description: "Mode.", Options: []string{"fast", "safe"}, DefaultValue: "safe"},
+			},
+			EnvVars: []EnvVar{{Name: "DEPLOY_TOKEN", Default: "none", Description: "Deployment token."}},
+		},
+		{
+			Name:        "repo",
+			Description: "Manage repositories.",
+			Subcommands: []Command{{Name: "list", Description: "List repositories."}},
+		},
+	},
+}
+
+func TestGenerateMarkdownDocs(t *testing.T) {
+	executable := coreutils.GetCliExecutableName()
+	expected := "# docs-plugin\n\n" +
+		"Docs plugin.\n\n" +
+		"**Version:** 1.0.0\n\n" +
+		"**Supported JFrog CLI core versions:** 2.0.0 or higher\n\n" +
+		"## Commands\n\n" +
+		"### docs-plugin deploy\n\n" +
+		"Deploy the application.\n\n" +
+		"**Usage:** `" + executable + " docs-plugin deploy --env=<value> [command options] <target>`\n\n" +
+		"**Aliases:** `d`\n\n" +
+		"#### Arguments\n\n| Name | Description |\n| --- | --- |\n" +
+		"| target | The deployment target. |\n\n" +
+		"#### Flags\n\n| Name | Type | Default | Mandatory | Description |\n| --- | --- | --- | --- | --- |\n" +
+		"| `--env` | string |  | Yes | Environment \\| stage. |\n" +
+		"| `--mode` | enum (fast, safe) | safe | No | Mode. |\n\n" +
+		"#### Environment Variables\n\n| Name | Default | Description |\n| --- | --- | --- |\n" +
+		"| `DEPLOY_TOKEN` | none | Deployment token. |\n\n" +
+		"### docs-plugin repo\n\n" +
+		"Manage repositories.\n\n" +
+		"### docs-plugin repo list\n\n" +
+		"List repositories.\n\n" +
+		"**Usage:** `" + executable + " docs-plugin repo list`\n"
+	assert.Equal(t, expected, GenerateMarkdownDocs(docsTestApp))
+}
+
+func TestGenerateJsonDocs(t *testing.T) {
+	content, err := GenerateJsonDocs(docsTestApp)
+	assert.NoError(t, err)
+	docs := new(PluginSignature)
+	assert.NoError(t, json.Unmarshal(content, docs))
+	assert.Equal(t, CreatePluginSignature(docsTestApp, ""), *docs)
+	assert.Empty(t, docs.CoreVersion)
+	assert.Equal(t, "list", docs.Commands[1].Subcommands[0].Name)
+	assert.True(t, docs.Commands[0].Flags[0].Mandatory)
+}
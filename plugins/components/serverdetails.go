@@ -0,0 +1,33 @@
+package components
+
+// The server selection flags, shared by the plugins commands.
+const (
+	ServerIdFlag          = "server-id"
+	UrlFlag               = "url"
+	UserFlag              = "user"
+	PasswordFlag          = "password"
+	AccessTokenFlag       = "access-token"
+	ClientCertPathFlag    = "client-cert-path"
+	ClientCertKeyPathFlag = "client-cert-key-path"
+	InsecureTlsFlag       = "insecure-tls"
+)
+
+// Returns the flags used by plugins.GetServerDetailsByFlags to select the server, similarly to the core commands.
+// Add these flags to every command which sends requests to the JFrog Platform.
+func GetServerFlags() []Flag {
+	return []Flag{
+		GetServerIdFlag(),
+		StringFlag{Name: UrlFlag, Description: "JFrog Platform URL. If provided without a server ID, the configured servers are ignored."},
+		StringFlag{Name: UserFlag, Description: "JFrog username."},
+		StringFlag{Name: PasswordFlag, Description: "JFrog password."},
+		StringFlag{Name: AccessTokenFlag, Description: "JFrog access token."},
+		StringFlag{Name: ClientCertPathFlag, Description: "Client certificate file in PEM format."},
+		StringFlag{Name: ClientCertKeyPathFlag, Description: "Private key file for the client certificate in PEM format."},
+		BoolFlag{Name: InsecureTlsFlag, Description: "Set to true to skip TLS certificates verification."},
+	}
+}
+
+// Returns the 'server-id' flag, which selects a configured server.
+func GetServerIdFlag() StringFlag {
+	return StringFlag{Name: ServerIdFlag, Description: "Server ID configured using the config command. If not provided, the default server is used."}
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+)
+
+// The flag types, as they appear in the plugin signature.
+const (
+	StringFlagType      = "string"
+	BoolFlagType        = "bool"
+	IntFlagType         = "int"
+	StringSliceFlagType = "string-slice"
+	EnumFlagType        = "enum"
+	DurationFlagType    = "duration"
+)
+
+// Creates the signature of the plugin, describing the plugin's commands and compatibility, so that the host CLI can use them without running the plugin.
+func CreatePluginSignature(jfrogApp App, coreVersion string) PluginSignature {
+	return PluginSignature{
+		Name:           jfrogApp.Name,
+		Usage:          jfrogApp.Description,
+		Version:        jfrogApp.Version,
+		CoreVersion:    coreVersion,
+		MinCoreVersion: jfrogApp.MinCoreVersion,
+		MaxCoreVersion: jfrogApp.MaxCoreVersion,
+		Commands:       createCommandsSignatures(jfrogApp.Commands),
+	}
+}
+
+func createCommandsSignatures(commands []Command) []CommandSignature {
+	var signatures []CommandSignature
+	for _, cmd := range commands {
+		signature := CommandSignature{
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			Aliases:     cmd.Aliases,
+			Subcommands: createCommandsSignatures(cmd.Subcommands),
+		}
+		for _, argument := range cmd.Arguments {
+			signature.Arguments = append(signature.Arguments, ArgumentSignature(argument))
+		}
+		for _, flag := range cmd.Flags {
+			signature.Flags = append(signature.Flags, createFlagSignature(flag))
+		}
+		for _, envVar := range cmd.EnvVars {
+			signature.EnvVars = append(signature.EnvVars, EnvVarSignature(envVar))
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
+
+func createFlagSignature(flag Flag) FlagSignature {
+	signature := FlagSignature{Name: flag.GetName(), Description: flag.GetDescription(), Mandatory: isMandatoryFlag(flag)}
+	switch f := flag.(type) {
+	case StringFlag:
+		signature.Type, signature.DefaultValue = StringFlagType, f.DefaultValue
+	case BoolFlag:
+		signature.Type, signature.DefaultValue = BoolFlagType, strconv.FormatBool(f.DefaultValue)
+	case IntFlag:
+		signature.Type, signature.MinValue, signature.MaxValue = IntFlagType, f.MinValue, f.MaxValue
+		if f.DefaultValue != 0 {
+			signature.DefaultValue = strconv.Itoa(f.DefaultValue)
+		}
+	case StringSliceFlag:
+		signature.Type, signature.DefaultValue = StringSliceFlagType, strings.Join(f.DefaultValue, ",")
+	case EnumFlag:
+		signature.Type, signature.DefaultValue, signature.Options = EnumFlagType, f.DefaultValue, f.Options
+	case DurationFlag:
+		signature.Type = DurationFlagType
+		if f.DefaultValue != 0 {
+			signature.DefaultValue = f.DefaultValue.String()
+		}
+	}
+	return signature
+}
+
+// Returns an error if the plugin doesn't support the provided core version of the host CLI.
+func (signature *PluginSignature) ValidateCoreVersion(coreVersion string) error {
+	if signature.MinCoreVersion != "" && !version.NewVersion(coreVersion).AtLeast(signature.MinCoreVersion) {
+		return fmt.Errorf("plugin '%s' requires JFrog CLI core version %s or higher, while the current version is %s", signature.Name, signature.MinCoreVersion, coreVersion)
+	}
+	if signature.MaxCoreVersion != "" && !version.NewVersion(signature.MaxCoreVersion).AtLeast(coreVersion) {
+		return fmt.Errorf("plugin '%s' supports JFrog CLI core version up to %s, while the current version is %s", signature.Name, signature.MaxCoreVersion, coreVersion)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePluginSignature(t *testing.T) {
+	maxValue := 10
+	app := App{
+		Name:           "test-plugin",
+		Description:    "Test plugin.",
+		Version:        "1.2.0",
+		MinCoreVersion: "2.0.0",
+		Commands: []Command{
+			{
+				Name:        "deploy",
+				Description: "Deploy the application.",
+				Aliases:     []string{"d"},
+				Arguments:   []Argument{{Name: "target", Description: "The deployment target."}},
+				Flags: []Flag{
+					StringFlag{Name: "env", Description: "Environment.", Mandatory: true},
+					IntFlag{Name: "retries", Description: "Retries.", DefaultValue: 3, MaxValue: &maxValue},
+					EnumFlag{Name: "mode", Description: "Mode.", Options: []string{"fast", "safe"}, DefaultValue: "safe"},
+					DurationFlag{Name: "timeout", Description: "Timeout.", DefaultValue: time.Minute},
+				},
+				EnvVars: []EnvVar{{Name: "DEPLOY_TOKEN", Description: "Deployment token."}},
+			},
+			{
+				Name:        "repo",
+				Description: "Manage repositories.",
+				Subcommands: []Command{{Name: "list", Description: "List repositories.", Flags: []Flag{BoolFlag{Name: "all", Description: "All."}}}},
+			},
+		},
+	}
+
+	signature := CreatePluginSignature(app, "2.5.0")
+	assert.Equal(t, "test-plugin", signature.Name)
+	assert.Equal(t, "Test plugin.", signature.Usage)
+	assert.Equal(t, "1.2.0", signature.Version)
+	assert.Equal(t, "2.5.0", signature.CoreVersion)
+	assert.Equal(t, "2.0.0", signature.MinCoreVersion)
+	assert.Empty(t, signature.MaxCoreVersion)
+	assert.Len(t, signature.Commands, 2)
+
+	deploy := signature.Commands[0]
+	assert.Equal(t, []string{"d"}, deploy.Aliases)
+	assert.Equal(t, []ArgumentSignature{{Name: "target", Description: "The deployment target."}}, deploy.Arguments)
+	assert.Equal(t, []EnvVarSignature{{Name: "DEPLOY_TOKEN", Description: "Deployment token."}}, deploy.EnvVars)
+	assert.Equal(t, []FlagSignature{
+		{Name: "env", Description: "Environment.", Type: StringFlagType, Mandatory: true},
+		{Name: "retries", Description: "Retries.", Type: IntFlagType, DefaultValue: "3", MaxValue: &maxValue},
+		{Name: "mode", Description: "Mode.", Type: EnumFlagType, DefaultValue: "safe", Options: []string{"fast", "safe"}},
+		{Name: "timeout", Description: "Timeout.", Type: DurationFlagType, DefaultValue: "1m0s"},
+	}, deploy.Flags)
+
+	repo := signature.Commands[1]
+	assert.Empty(t, repo.Flags)
+	assert.Equal(t, []CommandSignature{{Name: "list", Description: "List repositories.", Flags: []FlagSignature{{Name: "all", Description: "All.", Type: BoolFlagType, DefaultValue: "false"}}}}, repo.Subcommands)
+}
+
+func TestValidateCoreVersion(t *testing.T) {
+	testCases := []struct {
+		name           string
+		minCoreVersion string
+		maxCoreVersion string
+		coreVersion    string
+		expectError    bool
+	}{
+		{"noLimits", "", "", "2.5.0", false},
+		{"inRange", "2.0.0", "3.0.0", "2.5.0", false},
+		{"equalToMin", "2.5.0", "", "2.5.0", false},
+		{"equalToMax", "", "2.5.0", "2.5.0", false},
+		{"belowMin", "2.6.0", "", "2.5.0", true},
+		{"aboveMax", "", "2.4.9", "2.5.0", true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			signature := PluginSignature{Name: "test-plugin", MinCoreVersion: testCase.minCoreVersion, MaxCoreVersion: testCase.maxCoreVersion}
+			err := signature.ValidateCoreVersion(testCase.coreVersion)
+			if testCase.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ type App struct {
 	Description string
 	Version     string
 	Commands    []Command
+	// The range of the host CLI's core versions the plugin supports. Empty means no limit.
+	MinCoreVersion string
+	MaxCoreVersion string
 }
 
 type Command struct {
@@ -16,11 +19,52 @@ type Command struct {
 	EnvVars         []EnvVar
 	Action          ActionFunc
 	SkipFlagParsing bool
+	// Nested commands, invoked as '<command> <subcommand>'. The command's Action is optional if subcommands are defined.
+	Subcommands []Command
 }
 
 type PluginSignature struct {
-	Name  string `json:"name,omitempty"`
-	Usage string `json:"usage,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+	Version string `json:"version,omitempty"`
+	// The core version the plugin was built with.
+	CoreVersion    string             `json:"coreVersion,omitempty"`
+	MinCoreVersion string             `json:"minCoreVersion,omitempty"`
+	MaxCoreVersion string             `json:"maxCoreVersion,omitempty"`
+	Commands       []CommandSignature `json:"commands,omitempty"`
 	// Only used internally in the CLI.
 	ExecutablePath string `json:"executablePath,omitempty"`
 }
+
+type CommandSignature struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Aliases     []string            `json:"aliases,omitempty"`
+	Arguments   []ArgumentSignature `json:"arguments,omitempty"`
+	Flags       []FlagSignature     `json:"flags,omitempty"`
+	EnvVars     []EnvVarSignature   `json:"envVars,omitempty"`
+	Subcommands []CommandSignature  `json:"subcommands,omitempty"`
+}
+
+type ArgumentSignature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type EnvVarSignature struct {
+	Name        string `json:"name"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type FlagSignature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// One of the FlagType values.
+	Type         string   `json:"type"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
+	Mandatory    bool     `json:"mandatory,omitempty"`
+	Options      []string `json:"options,omitempty"`
+	MinValue     *int     `json:"minValue,omitempty"`
+	MaxValue     *int     `json:"maxValue,omitempty"`
+}
@@ -46,6 +46,22 @@ GLOBAL OPTIONS:
 
 `
 
+// Used for the help of commands with subcommands.
+const subcommandHelpTemplate = `NAME:
+   {{.HelpName}} - {{.Description}}
+
+USAGE:
+   {{.HelpName}} command [command options] [arguments...]
+
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{ "\t" }}{{if .Description}}{{.Description}}{{else}}{{.Usage}}{{end}}
+   {{end}}{{if .VisibleFlags}}
+OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}{{end}}
+
+`
+
 func PluginMain(jfrogApp components.App) {
 	log.SetDefaultLogger()
 
@@ -54,12 +70,13 @@ func PluginMain(jfrogApp components.App) {
 
 	cli.CommandHelpTemplate = commandHelpTemplate
 	cli.AppHelpTemplate = appHelpTemplate
+	cli.SubcommandHelpTemplate = subcommandHelpTemplate
 
 	baseApp, err := components.ConvertApp(jfrogApp)
 	if err != nil {
 		coreutils.ExitOnErr(err)
 	}
-	addHiddenPluginSignatureCommand(baseApp)
+	addHiddenPluginSignatureCommand(baseApp, jfrogApp)
 
 	args := os.Args
 	err = baseApp.Run(args)
@@ -0,0 +1,94 @@
+package plugins
+
+import (
+	"errors"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	xraycommands "github.com/jfrog/jfrog-cli-core/v2/xray/commands"
+	"github.com/jfrog/jfrog-client-go/access"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/xray"
+)
+
+// Returns the details of the server selected by the server flags:
+// If a server ID is provided, the configured server is used, and the other provided flags override its details.
+// If only a URL is provided, the server details are taken from the flags alone.
+// Otherwise, the default configured server is used.
+// The configuration is not modified. The resolved details are kept in the context, and returned by the following calls.
+func GetServerDetailsByFlags(c *components.Context) (*config.ServerDetails, error) {
+	if details := c.ServerDetails(); details != nil {
+		return details, nil
+	}
+	serverId := c.GetStringFlagValue(components.ServerIdFlag)
+	url := c.GetStringFlagValue(components.UrlFlag)
+	details := new(config.ServerDetails)
+	if serverId != "" || url == "" {
+		var err error
+		if details, err = config.GetSpecificConfig(serverId, true, false); err != nil {
+			return nil, err
+		}
+	}
+	if url != "" {
+		details.Url = clientutils.AddTrailingSlashIfNeeded(url)
+		details.ArtifactoryUrl = details.Url + "artifactory/"
+		details.DistributionUrl = details.Url + "distribution/"
+		details.XrayUrl = details.Url + "xray/"
+		details.MissionControlUrl = details.Url + "mc/"
+		details.PipelinesUrl = details.Url + "pipelines/"
+	}
+	overrideServerDetails(c, details)
+	if details.Url == "" && details.ArtifactoryUrl == "" {
+		return nil, errors.New("no server is configured. Use the 'jf config add' command, or provide the --" + components.UrlFlag + " flag")
+	}
+	details.Url = clientutils.AddTrailingSlashIfNeeded(details.Url)
+	c.SetServerDetails(details)
+	return details, nil
+}
+
+// Overrides the server details with the credentials provided as flags.
+func overrideServerDetails(c *components.Context, details *config.ServerDetails) {
+	user, password, accessToken := c.GetStringFlagValue(components.UserFlag), c.GetStringFlagValue(components.PasswordFlag), c.GetStringFlagValue(components.AccessTokenFlag)
+	if user != "" || password != "" || accessToken != "" {
+		// Credentials provided as flags replace the configured credentials, rather than being mixed with them.
+		details.User, details.Password = user, password
+		details.AccessToken, details.RefreshToken, details.ArtifactoryRefreshToken = accessToken, "", ""
+		details.ArtifactoryTokenRefreshInterval = 0
+		details.OidcProviderName = ""
+	}
+	if clientCertPath := c.GetStringFlagValue(components.ClientCertPathFlag); clientCertPath != "" {
+		details.ClientCertPath = clientCertPath
+	}
+	if clientCertKeyPath := c.GetStringFlagValue(components.ClientCertKeyPathFlag); clientCertKeyPath != "" {
+		details.ClientCertKeyPath = clientCertKeyPath
+	}
+	if c.GetBoolFlagValue(components.InsecureTlsFlag) {
+		details.InsecureTls = true
+	}
+}
+
+func CreateArtifactoryServiceManager(c *components.Context) (artifactory.ArtifactoryServicesManager, error) {
+	details, err := GetServerDetailsByFlags(c)
+	if err != nil {
+		return nil, err
+	}
+	return utils.CreateServiceManager(details, -1, 0, false)
+}
+
+func CreateXrayServiceManager(c *components.Context) (*xray.XrayServicesManager, error) {
+	details, err := GetServerDetailsByFlags(c)
+	if err != nil {
+		return nil, err
+	}
+	return xraycommands.CreateXrayServiceManager(details)
+}
+
+func CreateAccessServiceManager(c *components.Context) (*access.AccessServicesManager, error) {
+	details, err := GetServerDetailsByFlags(c)
+	if err != nil {
+		return nil, err
+	}
+	return utils.CreateAccessServiceManager(details, false)
+}
@@ -0,0 +1,111 @@
+package plugins
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetServerDetails(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	// No configured servers and no flags
+	_, err = GetServerDetailsByFlags(newServerFlagsContext(t, nil))
+	assert.Error(t, err)
+
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{
+		{ServerId: "default", Url: "https://default.jfrog.io/", ArtifactoryUrl: "https://default.jfrog.io/artifactory/", User: "admin", Password: "password", IsDefault: true},
+		{ServerId: "other", Url: "https://other.jfrog.io/", ArtifactoryUrl: "https://other.jfrog.io/artifactory/", AccessToken: "token"},
+	}))
+
+	testCases := []struct {
+		name                string
+		flags               map[string]string
+		expectedUrl         string
+		expectedUser        string
+		expectedAccessToken string
+	}{
+		{"default", nil, "https://default.jfrog.io/", "admin", ""},
+		{"serverId", map[string]string{components.ServerIdFlag: "other"}, "https://other.jfrog.io/", "", "token"},
+		{"serverIdWithCredentials", map[string]string{components.ServerIdFlag: "default", components.AccessTokenFlag: "flag-token"}, "https://default.jfrog.io/", "", "flag-token"},
+		{"urlOnly", map[string]string{components.UrlFlag: "https://flags.jfrog.io", components.UserFlag: "flags-user", components.PasswordFlag: "flags-password"}, "https://flags.jfrog.io/", "flags-user", ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			details, err := GetServerDetailsByFlags(newServerFlagsContext(t, testCase.flags))
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedUrl, details.Url)
+			assert.Equal(t, testCase.expectedUrl+"artifactory/", details.ArtifactoryUrl)
+			assert.Equal(t, testCase.expectedUser, details.User)
+			assert.Equal(t, testCase.expectedAccessToken, details.AccessToken)
+		})
+	}
+
+	// Credentials provided as flags replace the configured password
+	details, err := GetServerDetailsByFlags(newServerFlagsContext(t, map[string]string{components.AccessTokenFlag: "flag-token"}))
+	assert.NoError(t, err)
+	assert.Empty(t, details.Password)
+	_, err = GetServerDetailsByFlags(newServerFlagsContext(t, map[string]string{components.ServerIdFlag: "not-exist"}))
+	assert.Error(t, err)
+
+	// The resolved details are kept in the context
+	context := newServerFlagsContext(t, map[string]string{components.ServerIdFlag: "other"})
+	details, err = GetServerDetailsByFlags(context)
+	assert.NoError(t, err)
+	assert.Same(t, details, context.ServerDetails())
+
+	// The deprecated function resolves the server by the same flags
+	details, err = GetServerDetails(newServerFlagsContext(t, map[string]string{components.ServerIdFlag: "other"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://other.jfrog.io/", details.Url)
+	assert.Equal(t, "token", details.AccessToken)
+}
+
+func TestGetServerDetailsReadOnly(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	// A server which is configured to use refreshable tokens, but has none yet.
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{
+		{ServerId: "default", Url: "https://default.jfrog.io/", ArtifactoryUrl: "https://default.jfrog.io/artifactory/", User: "admin", Password: "password", ArtifactoryTokenRefreshInterval: 60, IsDefault: true},
+	}))
+	details, err := GetServerDetailsByFlags(newServerFlagsContext(t, nil))
+	assert.NoError(t, err)
+	assert.Empty(t, details.AccessToken)
+	servers, err := config.GetAllServersConfigs()
+	assert.NoError(t, err)
+	assert.Empty(t, servers[0].AccessToken)
+	assert.Empty(t, servers[0].ArtifactoryRefreshToken)
+}
+
+func TestCreateArtifactoryServiceManager(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/artifactory/api/system/ping", r.URL.Path)
+		assert.Equal(t, "Bearer flag-token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	servicesManager, err := CreateArtifactoryServiceManager(newServerFlagsContext(t, map[string]string{components.UrlFlag: ts.URL, components.AccessTokenFlag: "flag-token"}))
+	assert.NoError(t, err)
+	_, err = servicesManager.Ping()
+	assert.NoError(t, err)
+}
+
+func newServerFlagsContext(t *testing.T, flags map[string]string) *components.Context {
+	commandTest := components.NewCommandTest(components.Command{Name: "test", Flags: components.GetServerFlags()})
+	for name, value := range flags {
+		commandTest.SetFlag(name, value)
+	}
+	context, err := commandTest.CreateContext()
+	assert.NoError(t, err)
+	return context
+}
@@ -2,6 +2,7 @@ package plugins
 
 import (
 	"encoding/json"
+	jfrogclicore "github.com/jfrog/jfrog-cli-core/v2"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -11,17 +12,15 @@ import (
 const SignatureCommandName = "hidden-plugin-signature"
 
 // Adds a hidden command to every built plugin.
-// The command will later be used by the CLI to retrieve the plugin's signature to show in the CLI's help command.
-func addHiddenPluginSignatureCommand(baseApp *cli.App) {
+// The command will later be used by the CLI to retrieve the plugin's signature, which includes the plugin's commands and the supported core versions.
+// The CLI uses the signature to show the plugin in its help command, generate completion and validate compatibility, without executing the plugin's commands.
+func addHiddenPluginSignatureCommand(baseApp *cli.App, jfrogApp components.App) {
 	cmd := cli.Command{
 		Name:     SignatureCommandName,
 		Hidden:   true,
 		HideHelp: true,
 		Action: func(c *cli.Context) error {
-			signature := components.PluginSignature{
-				Name:  baseApp.Name,
-				Usage: baseApp.Description,
-			}
+			signature := components.CreatePluginSignature(jfrogApp, jfrogclicore.GetVersion())
 			content, err := json.Marshal(signature)
 			if err == nil {
 				log.Output(clientutils.IndentJson(content))
@@ -42,22 +42,27 @@ func IsServerConfExists() (bool, error) {
 // Returns the configured server or error if the server id was not found.
 // If defaultOrEmpty: return empty details if no configurations found, or default conf for empty serverId.
 // Exclude refreshable tokens when working with external tools (build tools, curl, etc.) or when sending requests not via ArtifactoryHttpClient.
+// The default server is the server of the active context, if set. See GetActiveContext.
 func GetSpecificConfig(serverId string, defaultOrEmpty bool, excludeRefreshableTokens bool) (*ServerDetails, error) {
-	configs, err := GetAllServersConfigs()
+	conf, err := readConf()
 	if err != nil {
 		return nil, err
 	}
+	configs := conf.Servers
 
 	if defaultOrEmpty {
 		if len(configs) == 0 {
 			return new(ServerDetails), nil
 		}
 		if len(serverId) == 0 {
-			details, err := GetDefaultConfiguredConf(configs)
+			details, err := conf.getDefaultServerConf()
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
 			if excludeRefreshableTokens {
 				excludeRefreshableTokensFromDetails(details)
 			}
-			return details, errorutils.CheckError(err)
+			return details, nil
 		}
 	}
 
@@ -99,18 +104,37 @@ func GetDefaultConfiguredConf(configs []*ServerDetails) (*ServerDetails, error)
 }
 
 // Returns default artifactory conf. Returns nil if default server doesn't exists.
+// The default server is the server of the active context, if set. See GetActiveContext.
 func GetDefaultServerConf() (*ServerDetails, error) {
-	configurations, err := GetAllServersConfigs()
+	conf, err := readConf()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(configurations) == 0 {
+	if len(conf.Servers) == 0 {
 		log.Debug("No servers were configured.")
 		return nil, err
 	}
 
-	return GetDefaultConfiguredConf(configurations)
+	return conf.getDefaultServerConf()
+}
+
+// Returns the server of the active context if set, or the default server otherwise.
+// An active context which doesn't exist, or which points to a server which doesn't exist, is ignored with a warning.
+func (config *Config) getDefaultServerConf() (*ServerDetails, error) {
+	context, err := config.getActiveContext()
+	if err != nil {
+		log.Warn("Couldn't resolve the server from the active context, using the default server: " + err.Error())
+		return GetDefaultConfiguredConf(config.Servers)
+	}
+	if context != nil && context.ServerId != "" {
+		serverDetails, err := getServerConfByServerId(context.ServerId, config.Servers)
+		if err == nil {
+			return serverDetails, nil
+		}
+		log.Warn("Couldn't resolve the server of the '" + context.Name + "' context, using the default server: " + err.Error())
+	}
+	return GetDefaultConfiguredConf(config.Servers)
 }
 
 // Returns the configured server or error if the server id not found
@@ -150,49 +174,54 @@ func SaveServersConf(details []*ServerDetails) error {
 	if err != nil {
 		return err
 	}
+	previous := &Config{}
+	previous.SecretStore = conf.SecretStore
+	previous.Servers = conf.Servers
 	conf.Servers = details
+	conf.detachRemovedServersFromContexts()
 	conf.Version = strconv.Itoa(coreutils.GetCliConfigVersion())
-	return saveConfig(conf)
-}
-
-func saveConfig(config *Config) error {
-	cloneConfig, err := config.Clone()
-	if err != nil {
-		return err
-	}
-	err = cloneConfig.encrypt()
-	if err != nil {
-		return err
-	}
-
-	content, err := cloneConfig.getContent()
-	if err != nil {
-		return err
-	}
-
-	path, err := getConfFilePath()
-	if err != nil {
+	if err = saveConfig(conf); err != nil {
 		return err
 	}
+	return eraseRemovedSecrets(previous, conf)
+}
 
-	err = os.WriteFile(path, content, 0600)
-	if err != nil {
-		return errorutils.CheckError(err)
+// Saves the config to the config file.
+// The file is written atomically while holding the config write lock, to prevent corrupting it by concurrent writes of other processes.
+func saveConfig(config *Config) error {
+	// A new config, which wasn't read from an existing file, is created in the latest version.
+	if config.Version == "" {
+		config.Version = strconv.Itoa(coreutils.GetCliConfigVersion())
 	}
-	return nil
+	defer resetActiveContextCache()
+	return writeConfigFile(config, func() ([]byte, error) {
+		cloneConfig, err := config.Clone()
+		if err != nil {
+			return nil, err
+		}
+		err = cloneConfig.storeSecrets()
+		if err != nil {
+			return nil, err
+		}
+		err = cloneConfig.encrypt()
+		if err != nil {
+			return nil, err
+		}
+		return cloneConfig.getContent()
+	})
 }
 
 func readConf() (*Config, error) {
 	config := new(Config)
-	content, err := getConfigFile()
+	rawContent, err := getConfigFile()
 	if err != nil {
 		return nil, err
 	}
-	if len(content) == 0 {
+	if len(rawContent) == 0 {
 		// No config file was found, returns a new empty config.
 		return config, nil
 	}
-	content, err = convertIfNeeded(content)
+	content, err := convertIfNeeded(rawContent)
 	if err != nil {
 		return nil, err
 	}
@@ -202,8 +231,19 @@ func readConf() (*Config, error) {
 		return nil, errorutils.CheckError(err)
 	}
 
+	err = config.resolveSecrets()
+	if err != nil {
+		return nil, err
+	}
 	err = config.decrypt()
-	return config, err
+	if err != nil {
+		return nil, err
+	}
+	// The baseline may have already been set, if the config was saved while decrypting.
+	if config.baseline == nil {
+		config.baseline = newConfigBaseline(rawContent, config)
+	}
+	return config, nil
 }
 
 func getConfigFile() (content []byte, err error) {
@@ -316,18 +356,12 @@ func convertIfNeeded(content []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	// Switch contains FALLTHROUGH to convert from a certain version to the latest.
-	switch version {
-	case strconv.Itoa(coreutils.GetCliConfigVersion()):
+	if version == strconv.Itoa(coreutils.GetCliConfigVersion()) {
 		return content, nil
-	case "0":
-		content, err = convertConfigV0toV1(content)
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "1":
+	}
+
+	// The homedir layout was changed in version 2.
+	if version == "0" || version == "1" {
 		err = createHomeDirBackup()
 		if err != nil {
 			return nil, err
@@ -336,22 +370,8 @@ func convertIfNeeded(content []byte) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
-		fallthrough
-	case "2":
-		content, err = convertConfigV2toV3(content)
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "3", "4":
-		content, err = convertConfigV4toV5(content)
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "5":
-		content, err = convertConfigV5toV6(content)
 	}
+	content, err = convertConfigSchema(content, version)
 	if err != nil {
 		return nil, err
 	}
@@ -374,6 +394,34 @@ func convertIfNeeded(content []byte) ([]byte, error) {
 	return content, err
 }
 
+// Converts the config content from the provided schema version to the latest schema, without any side effects.
+func convertConfigSchema(content []byte, version string) (result []byte, err error) {
+	// Switch contains FALLTHROUGH to convert from a certain version to the latest.
+	switch version {
+	case "0":
+		content, err = convertConfigV0toV1(content)
+		if err != nil {
+			return nil, err
+		}
+		fallthrough
+	case "1", "2":
+		content, err = convertConfigV2toV3(content)
+		if err != nil {
+			return nil, err
+		}
+		fallthrough
+	case "3", "4":
+		content, err = convertConfigV4toV5(content)
+		if err != nil {
+			return nil, err
+		}
+		fallthrough
+	case "5":
+		content, err = convertConfigV5toV6(content)
+	}
+	return content, err
+}
+
 // Creating a homedir backup prior to converting.
 func createHomeDirBackup() error {
 	homeDir, err := coreutils.GetJfrogHomeDir()
@@ -508,6 +556,12 @@ type ConfigV5 struct {
 	Servers []*ServerDetails `json:"servers"`
 	Version string           `json:"version,omitempty"`
 	Enc     bool             `json:"enc,omitempty"`
+	// The secret store backend. The secrets are kept in the config file if empty.
+	SecretStore    *SecretStoreDetails `json:"secretStore,omitempty"`
+	Contexts       []*ContextDetails   `json:"contexts,omitempty"`
+	CurrentContext string              `json:"currentContext,omitempty"`
+	// The state of the config file when it was read.
+	baseline *configBaseline
 }
 
 // This struct is suitable for versions 1, 2, 3 and 4.
@@ -577,8 +631,12 @@ type ServerDetails struct {
 	ArtifactoryTokenRefreshInterval int    `json:"tokenRefreshInterval,omitempty"`
 	ClientCertPath                  string `json:"clientCertPath,omitempty"`
 	ClientCertKeyPath               string `json:"clientCertKeyPath,omitempty"`
+	OidcProviderName                string `json:"oidcProviderName,omitempty"`
+	OidcTokenFile                   string `json:"oidcTokenFile,omitempty"`
+	OidcTokenEnvVar                 string `json:"oidcTokenEnvVar,omitempty"`
 	ServerId                        string `json:"serverId,omitempty"`
 	IsDefault                       bool   `json:"isDefault,omitempty"`
+	SecretsRef                      string `json:"secretsRef,omitempty"`
 	InsecureTls                     bool   `json:"-"`
 }
 
@@ -711,7 +769,10 @@ func (serverDetails *ServerDetails) createAuthConfig(details auth.ServiceDetails
 	details.SetAccessToken(serverDetails.AccessToken)
 	// If refresh token is not empty, set a refresh handler and skip other credentials.
 	// First we check access's token, if empty we check artifactory's token.
-	if serverDetails.RefreshToken != "" {
+	// If an OIDC provider is configured, the access token is acquired by exchanging the CI provided OIDC ID token.
+	if serverDetails.OidcProviderName != "" {
+		details.AppendPreRequestFunction(newOidcTokenExchangePreRequestInterceptor(serverDetails))
+	} else if serverDetails.RefreshToken != "" {
 		// Save serverId for refreshing if needed. If empty serverId is saved, default will be used.
 		tokenRefreshServerId = serverDetails.ServerId
 		details.AppendPreRequestFunction(AccessTokenRefreshPreRequestInterceptor)
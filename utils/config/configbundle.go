@@ -0,0 +1,242 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	bundleVersion    = 1
+	bundleSaltLength = 16
+	// The scrypt parameters used for deriving the encryption key from the passphrase.
+	bundleScryptN = 32768
+	bundleScryptR = 8
+	bundleScryptP = 1
+)
+
+// Determines how an imported server is handled if a server with the same ID already exists.
+type ImportConflictPolicy string
+
+const (
+	// Non-empty fields of the imported server override the fields of the existing server.
+	MergeOnConflict ImportConflictPolicy = "merge"
+	// The imported server replaces the existing server.
+	ReplaceOnConflict ImportConflictPolicy = "replace"
+	// The existing server is kept, and the imported server is skipped.
+	SkipOnConflict ImportConflictPolicy = "skip"
+)
+
+// A portable bundle of servers configurations, encrypted with a passphrase.
+// Similarly to the config token, the bundle is serialized as a base64 encoded JSON.
+type configBundle struct {
+	Version int `json:"version,omitempty"`
+	// The salt used for deriving the encryption key from the passphrase.
+	Salt string `json:"salt,omitempty"`
+	// The encrypted bundleContent, holding the exported servers.
+	Data string `json:"data,omitempty"`
+}
+
+// The exported servers, in the same representation as the config token, so that the bundle and the token are imported in the same way.
+type bundleContent struct {
+	Servers []*configToken `json:"servers,omitempty"`
+}
+
+// Exports the servers with the provided IDs as a bundle encrypted with the passphrase. If no IDs are provided, all servers are exported.
+func ExportBundle(serverIds []string, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errorutils.CheckErrorf("a passphrase is required for exporting a config bundle")
+	}
+	conf, err := readConf()
+	if err != nil {
+		return "", err
+	}
+	if err = verifyMasterKeyIfEncrypted(conf, "config bundle"); err != nil {
+		return "", err
+	}
+	servers, err := filterServers(conf.Servers, serverIds)
+	if err != nil {
+		return "", err
+	}
+	if len(servers) == 0 {
+		return "", errorutils.CheckErrorf("cannot export config bundle, because there are no servers to export")
+	}
+	exported := &bundleContent{}
+	for _, serverDetails := range servers {
+		exported.Servers = append(exported.Servers, fromServerDetails(serverDetails))
+	}
+	content, err := json.Marshal(exported)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	salt := make([]byte, bundleSaltLength)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	key, err := deriveBundleKey(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	data, err := encrypt(string(content), key)
+	if err != nil {
+		return "", err
+	}
+	buffer, err := json.Marshal(&configBundle{Version: bundleVersion, Salt: base64.StdEncoding.EncodeToString(salt), Data: data})
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return base64.StdEncoding.EncodeToString(buffer), nil
+}
+
+// Imports the servers from a bundle created by ExportBundle, or from a single server config token created by Export.
+// Returns the IDs of the imported servers.
+func ImportBundle(bundleString, passphrase string, policy ImportConflictPolicy) ([]string, error) {
+	servers, err := decodeBundle(bundleString, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := readConf()
+	if err != nil {
+		return nil, err
+	}
+	var imported []string
+	conf.Servers, imported, err = mergeImportedServers(conf.Servers, servers, policy)
+	if err != nil {
+		return nil, err
+	}
+	conf.Version = strconv.Itoa(coreutils.GetCliConfigVersion())
+	return imported, saveConfig(conf)
+}
+
+func decodeBundle(bundleString, passphrase string) ([]*ServerDetails, error) {
+	decoded, err := base64.StdEncoding.DecodeString(bundleString)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	bundle := &configBundle{}
+	if err = json.Unmarshal(decoded, bundle); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if bundle.Data == "" {
+		// Not a bundle, but a single server config token.
+		serverDetails, err := Import(bundleString)
+		if err != nil {
+			return nil, err
+		}
+		return []*ServerDetails{serverDetails}, nil
+	}
+	if bundle.Version > bundleVersion {
+		return nil, errorutils.CheckErrorf("unsupported config bundle version %d. Please upgrade JFrog CLI", bundle.Version)
+	}
+	salt, err := base64.StdEncoding.DecodeString(bundle.Salt)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	key, err := deriveBundleKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	content, err := decrypt(bundle.Data, key)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("could not decrypt the config bundle. Make sure the passphrase is correct")
+	}
+	imported := &bundleContent{}
+	if err = json.Unmarshal([]byte(content), imported); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var servers []*ServerDetails
+	for _, token := range imported.Servers {
+		servers = append(servers, tokenToServerDetails(token))
+	}
+	return servers, nil
+}
+
+func deriveBundleKey(passphrase string, salt []byte) (string, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, bundleScryptN, bundleScryptR, bundleScryptP, masterKeyLength)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return string(key), nil
+}
+
+// Returns the servers with the provided IDs, or all servers if no IDs are provided.
+func filterServers(servers []*ServerDetails, serverIds []string) ([]*ServerDetails, error) {
+	if len(serverIds) == 0 {
+		return servers, nil
+	}
+	var filtered []*ServerDetails
+	for _, serverId := range serverIds {
+		serverDetails, err := getServerConfByServerId(serverId, servers)
+		if err != nil {
+			return nil, err
+		}
+		filtered = append(filtered, serverDetails)
+	}
+	return filtered, nil
+}
+
+// Adds the imported servers to the existing servers according to the conflict policy.
+// Returns the updated servers list and the IDs of the imported servers.
+func mergeImportedServers(existing, imported []*ServerDetails, policy ImportConflictPolicy) ([]*ServerDetails, []string, error) {
+	switch policy {
+	case MergeOnConflict, ReplaceOnConflict, SkipOnConflict:
+	default:
+		return nil, nil, errorutils.CheckErrorf("unsupported import conflict policy: '%s'", policy)
+	}
+	var importedIds []string
+	for _, serverDetails := range imported {
+		serverDetails.IsDefault = false
+		serverDetails.SecretsRef = ""
+		index := -1
+		for i, existingDetails := range existing {
+			if existingDetails.ServerId == serverDetails.ServerId {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			existing = append(existing, serverDetails)
+			importedIds = append(importedIds, serverDetails.ServerId)
+			continue
+		}
+		switch policy {
+		case SkipOnConflict:
+			log.Info("Server ID '" + serverDetails.ServerId + "' already exists, skipping.")
+			continue
+		case ReplaceOnConflict:
+			serverDetails.IsDefault = existing[index].IsDefault
+			existing[index] = serverDetails
+		case MergeOnConflict:
+			if err := mergeServerDetails(existing[index], serverDetails); err != nil {
+				return nil, nil, err
+			}
+		}
+		importedIds = append(importedIds, serverDetails.ServerId)
+	}
+	if _, err := GetDefaultConfiguredConf(existing); err != nil && len(existing) > 0 {
+		existing[0].IsDefault = true
+	}
+	return existing, importedIds, nil
+}
+
+// Copies the non-empty fields of the source server to the target server.
+func mergeServerDetails(target, source *ServerDetails) error {
+	isDefault, secretsRef := target.IsDefault, target.SecretsRef
+	// All the serialized fields are omitted if empty, so only non-empty fields override the target's fields.
+	content, err := json.Marshal(source)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(content, target); err != nil {
+		return errorutils.CheckError(err)
+	}
+	target.IsDefault, target.SecretsRef = isDefault, secretsRef
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigBundleExportImport(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "server-1", Url: "http://server1/", User: "admin", Password: "password", IsDefault: true},
+		{ServerId: "server-2", Url: "http://server2/", AccessToken: "token"},
+		{ServerId: "server-3", Url: "http://server3/"},
+	}))
+	bundle, err := ExportBundle([]string{"server-1", "server-2"}, "passphrase")
+	assert.NoError(t, err)
+	// A passphrase is required and servers must exist
+	_, err = ExportBundle(nil, "")
+	assert.Error(t, err)
+	_, err = ExportBundle([]string{"not-exist"}, "passphrase")
+	assert.Error(t, err)
+
+	// The servers are exported in the same representation as the config token
+	server1, err := GetSpecificConfig("server-1", false, false)
+	assert.NoError(t, err)
+	token, err := Export(server1)
+	assert.NoError(t, err)
+	expectedServer1, err := Import(token)
+	assert.NoError(t, err)
+	bundleServers, err := decodeBundle(bundle, "passphrase")
+	assert.NoError(t, err)
+	if assert.Len(t, bundleServers, 2) {
+		assert.Equal(t, expectedServer1, bundleServers[0])
+	}
+
+	// Import into an empty config
+	assert.NoError(t, SaveServersConf([]*ServerDetails{}))
+	_, err = ImportBundle(bundle, "wrong-passphrase", MergeOnConflict)
+	assert.Error(t, err)
+	imported, err := ImportBundle(bundle, "passphrase", MergeOnConflict)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"server-1", "server-2"}, imported)
+
+	servers, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	assert.Len(t, servers, 2)
+	server1, err = GetSpecificConfig("server-1", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "password", server1.Password)
+	assert.True(t, server1.IsDefault)
+	server2, err := GetSpecificConfig("server-2", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", server2.AccessToken)
+	assert.False(t, server2.IsDefault)
+}
+
+func TestConfigBundleConflictPolicies(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "server", Url: "http://imported/", User: "imported-user"}}))
+	bundle, err := ExportBundle(nil, "passphrase")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		policy        ImportConflictPolicy
+		expectedUrl   string
+		expectedUser  string
+		expectedToken string
+	}{
+		{SkipOnConflict, "http://existing/", "", "existing-token"},
+		{ReplaceOnConflict, "http://imported/", "imported-user", ""},
+		{MergeOnConflict, "http://imported/", "imported-user", "existing-token"},
+	}
+	for _, testCase := range testCases {
+		t.Run(string(testCase.policy), func(t *testing.T) {
+			assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "server", Url: "http://existing/", AccessToken: "existing-token", IsDefault: true}}))
+			_, err = ImportBundle(bundle, "passphrase", testCase.policy)
+			assert.NoError(t, err)
+			details, err := GetSpecificConfig("server", false, false)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedUrl, details.Url)
+			assert.Equal(t, testCase.expectedUser, details.User)
+			assert.Equal(t, testCase.expectedToken, details.AccessToken)
+			assert.True(t, details.IsDefault)
+		})
+	}
+
+	_, err = ImportBundle(bundle, "passphrase", "unknown")
+	assert.Error(t, err)
+}
+
+func TestConfigBundleImportConfigToken(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	imported, err := ImportBundle(v2Token, "", MergeOnConflict)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"local"}, imported)
+	details, err := GetSpecificConfig("local", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:8081/artifactory/", details.ArtifactoryUrl)
+	assert.Equal(t, "password", details.Password)
+}
@@ -84,19 +84,8 @@ func Export(details *ServerDetails) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	// If config is encrypted, ask for master key.
-	if conf.Enc {
-		masterKeyFromFile, err := getEncryptionKey()
-		if err != nil {
-			return "", err
-		}
-		masterKeyFromConsole, err := readMasterKeyFromConsole()
-		if err != nil {
-			return "", err
-		}
-		if masterKeyFromConsole != masterKeyFromFile {
-			return "", errorutils.CheckErrorf("could not generate config token: config is encrypted, and wrong master key was provided")
-		}
+	if err = verifyMasterKeyIfEncrypted(conf, "config token"); err != nil {
+		return "", err
 	}
 	buffer, err := json.Marshal(fromServerDetails(details))
 	if err != nil {
@@ -114,8 +103,32 @@ func Import(configTokenString string) (*ServerDetails, error) {
 	if err = json.Unmarshal(decoded, token); err != nil {
 		return nil, err
 	}
+	return tokenToServerDetails(token), nil
+}
+
+// Converts a token of any version to the server details.
+func tokenToServerDetails(token *configToken) *ServerDetails {
 	if token.Version < tokenVersion {
 		token.convertToV2()
 	}
-	return toServerDetails(token), nil
+	return toServerDetails(token)
+}
+
+// If the config is encrypted, asks for the master key and verifies it before exporting secrets.
+func verifyMasterKeyIfEncrypted(conf *Config, exportedItem string) error {
+	if !conf.Enc {
+		return nil
+	}
+	masterKeyFromFile, err := getEncryptionKey()
+	if err != nil {
+		return err
+	}
+	masterKeyFromConsole, err := readMasterKeyFromConsole()
+	if err != nil {
+		return err
+	}
+	if masterKeyFromConsole != masterKeyFromFile {
+		return errorutils.CheckErrorf("could not generate %s: config is encrypted, and wrong master key was provided", exportedItem)
+	}
+	return nil
 }
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The state of the config file when the config was read. Used for detecting and merging concurrent modifications.
+type configBaseline struct {
+	// The checksum of the config file content.
+	checksum string
+	// The decrypted servers, as read from the config file.
+	servers        []ServerDetails
+	secretStore    *SecretStoreDetails
+	contexts       []ContextDetails
+	currentContext string
+	enc            bool
+}
+
+func newConfigBaseline(content []byte, config *Config) *configBaseline {
+	baseline := &configBaseline{checksum: contentChecksum(content), currentContext: config.CurrentContext, enc: config.Enc}
+	for _, serverDetails := range config.Servers {
+		baseline.servers = append(baseline.servers, *serverDetails)
+	}
+	if config.SecretStore != nil {
+		secretStore := *config.SecretStore
+		baseline.secretStore = &secretStore
+	}
+	for _, context := range config.Contexts {
+		baseline.contexts = append(baseline.contexts, *context)
+	}
+	return baseline
+}
+
+func contentChecksum(content []byte) string {
+	checksum := sha256.Sum256(content)
+	return hex.EncodeToString(checksum[:])
+}
+
+// Acquires the config write lock, preventing other processes from writing the config file.
+func lockConfigWrite() (unlock func() error, err error) {
+	lockDirPath, err := coreutils.GetJfrogConfigWriteLockDir()
+	if err != nil {
+		return func() error { return nil }, err
+	}
+	return lock.CreateLock(lockDirPath)
+}
+
+// Runs the action while holding the config write lock.
+func withConfigWriteLock(action func() error) (err error) {
+	unlock, err := lockConfigWrite()
+	// Defer the unlock function before throwing a possible error to avoid deadlock situations.
+	defer func() {
+		e := unlock()
+		if err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+	return action()
+}
+
+// Writes the config file content while holding the config write lock.
+// If the config file was modified by another process since the config was read, the modifications are merged into the config first.
+func writeConfigFile(config *Config, getContent func() ([]byte, error)) error {
+	return withConfigWriteLock(func() error {
+		return writeConfigFileLocked(config, getContent)
+	})
+}
+
+// Same as writeConfigFile, for callers which already hold the config write lock.
+func writeConfigFileLocked(config *Config, getContent func() ([]byte, error)) (err error) {
+	path, err := getConfFilePath()
+	if err != nil {
+		return
+	}
+	if err = mergeConcurrentModifications(config, path); err != nil {
+		return
+	}
+	content, err := getContent()
+	if err != nil {
+		return
+	}
+	if err = writeFileAtomically(path, content); err != nil {
+		return
+	}
+	config.baseline = newConfigBaseline(content, config)
+	return
+}
+
+// If the config file was modified since the config was read, merges the modifications into the config.
+// Each top-level field is merged against the baseline: fields we didn't modify are taken from the modified file.
+func mergeConcurrentModifications(config *Config, path string) error {
+	if config.baseline == nil {
+		return nil
+	}
+	exists, err := fileutils.IsFileExists(path, false)
+	if err != nil || !exists {
+		return err
+	}
+	content, err := fileutils.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if contentChecksum(content) == config.baseline.checksum {
+		return nil
+	}
+	log.Debug("The config file was modified by another process. Merging the modifications...")
+	diskConfig, err := parseConfigWithoutUpdate(content)
+	if err != nil {
+		log.Warn("Couldn't merge the modifications made to the config file by another process, the modifications will be overridden: " + err.Error())
+		return nil
+	}
+	baseline := config.baseline
+	config.Servers = mergeServers(baseline.servers, config.Servers, diskConfig.Servers)
+	config.Contexts = mergeContexts(baseline.contexts, config.Contexts, diskConfig.Contexts)
+	if reflect.DeepEqual(baseline.secretStore, config.SecretStore) {
+		config.SecretStore = diskConfig.SecretStore
+	}
+	if baseline.currentContext == config.CurrentContext {
+		config.CurrentContext = diskConfig.CurrentContext
+	}
+	if baseline.enc == config.Enc {
+		config.Enc = diskConfig.Enc
+	}
+	return nil
+}
+
+// Parses and decrypts the content of a config file in the latest version, without saving any update to the file.
+func parseConfigWithoutUpdate(content []byte) (*Config, error) {
+	version, err := getVersion(content)
+	if err != nil {
+		return nil, err
+	}
+	if version != strconv.Itoa(coreutils.GetCliConfigVersion()) {
+		return nil, errorutils.CheckErrorf("unexpected config version: %s", version)
+	}
+	config := new(Config)
+	if err = json.Unmarshal(content, &config); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err = config.resolveSecrets(); err != nil {
+		return nil, err
+	}
+	if !config.Enc {
+		return config, nil
+	}
+	key, err := getEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	config.Enc = false
+	return config, handleSecrets(config, decrypt, key)
+}
+
+// Three-way merge of the servers lists.
+// Servers added, modified or removed by us (compared to the baseline) are taken from ours, and all others are taken from theirs.
+func mergeServers(baseline []ServerDetails, ours, theirs []*ServerDetails) []*ServerDetails {
+	baselineById := make(map[string]ServerDetails)
+	for _, serverDetails := range baseline {
+		baselineById[serverDetails.ServerId] = serverDetails
+	}
+	theirsById := make(map[string]*ServerDetails)
+	for _, serverDetails := range theirs {
+		theirsById[serverDetails.ServerId] = serverDetails
+	}
+	oursById := make(map[string]bool)
+	var merged []*ServerDetails
+	var changedDefault *ServerDetails
+	for _, serverDetails := range ours {
+		oursById[serverDetails.ServerId] = true
+		baselineDetails, inBaseline := baselineById[serverDetails.ServerId]
+		if inBaseline && reflect.DeepEqual(baselineDetails, *serverDetails) {
+			// Not modified by us
+			if theirsDetails, exists := theirsById[serverDetails.ServerId]; exists {
+				merged = append(merged, theirsDetails)
+			}
+			continue
+		}
+		if serverDetails.IsDefault {
+			changedDefault = serverDetails
+		}
+		merged = append(merged, serverDetails)
+	}
+	for _, serverDetails := range theirs {
+		_, inBaseline := baselineById[serverDetails.ServerId]
+		if oursById[serverDetails.ServerId] || inBaseline {
+			// Exists in ours, or removed by us
+			continue
+		}
+		merged = append(merged, serverDetails)
+	}
+	// Make sure there's a single default server, preferring the default set by us.
+	var defaultFound bool
+	for _, serverDetails := range merged {
+		isDefault := serverDetails.IsDefault && !defaultFound && (changedDefault == nil || changedDefault == serverDetails)
+		defaultFound = defaultFound || isDefault
+		serverDetails.IsDefault = isDefault
+	}
+	return merged
+}
+
+// Three-way merge of the contexts lists, similar to mergeServers.
+func mergeContexts(baseline []ContextDetails, ours, theirs []*ContextDetails) []*ContextDetails {
+	baselineByName := make(map[string]ContextDetails)
+	for _, context := range baseline {
+		baselineByName[context.Name] = context
+	}
+	theirsByName := make(map[string]*ContextDetails)
+	for _, context := range theirs {
+		theirsByName[context.Name] = context
+	}
+	oursByName := make(map[string]bool)
+	var merged []*ContextDetails
+	for _, context := range ours {
+		oursByName[context.Name] = true
+		if baselineContext, inBaseline := baselineByName[context.Name]; inBaseline && baselineContext == *context {
+			// Not modified by us
+			if theirsContext, exists := theirsByName[context.Name]; exists {
+				merged = append(merged, theirsContext)
+			}
+			continue
+		}
+		merged = append(merged, context)
+	}
+	for _, context := range theirs {
+		_, inBaseline := baselineByName[context.Name]
+		if oursByName[context.Name] || inBaseline {
+			// Exists in ours, or removed by us
+			continue
+		}
+		merged = append(merged, context)
+	}
+	return merged
+}
+
+// Writes the content to a temporary file in the target directory, and then renames it to the target path.
+// This guarantees readers never see a partially written file.
+func writeFileAtomically(path string, content []byte) (err error) {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tempFile.Name())
+		}
+	}()
+	if _, err = tempFile.Write(content); err != nil {
+		_ = tempFile.Close()
+		return errorutils.CheckError(err)
+	}
+	if err = tempFile.Sync(); err != nil {
+		_ = tempFile.Close()
+		return errorutils.CheckError(err)
+	}
+	if err = tempFile.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.Chmod(tempFile.Name(), 0600); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.Rename(tempFile.Name(), path))
+}
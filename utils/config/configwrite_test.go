@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveConfigMergesConcurrentModifications(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "first", AccessToken: "token1", IsDefault: true},
+		{ServerId: "second", AccessToken: "token2"},
+		{ServerId: "third", AccessToken: "token3"},
+	}))
+
+	// Simulate two processes reading the config at the same time
+	firstConf, err := readConf()
+	assert.NoError(t, err)
+	secondConf, err := readConf()
+	assert.NoError(t, err)
+
+	// The first process refreshes the token of the first server and removes the third server
+	firstConf.Servers[0].AccessToken = "new-token1"
+	firstConf.Servers = firstConf.Servers[:2]
+	assert.NoError(t, saveConfig(firstConf))
+
+	// The second process refreshes the token of the second server and adds a new server
+	secondConf.Servers[1].AccessToken = "new-token2"
+	secondConf.Servers = append(secondConf.Servers, &ServerDetails{ServerId: "fourth"})
+	assert.NoError(t, saveConfig(secondConf))
+
+	servers, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	serverIds := make(map[string]*ServerDetails)
+	for _, serverDetails := range servers {
+		serverIds[serverDetails.ServerId] = serverDetails
+	}
+	assert.Len(t, serverIds, 3)
+	assert.NotContains(t, serverIds, "third")
+	assert.Equal(t, "new-token1", serverIds["first"].AccessToken)
+	assert.Equal(t, "new-token2", serverIds["second"].AccessToken)
+	assert.Contains(t, serverIds, "fourth")
+	assert.True(t, serverIds["first"].IsDefault)
+}
+
+func TestSaveConfigMergesConcurrentContextsModifications(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "first", AccessToken: "token1", IsDefault: true}}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "dev", Project: "dev-project"}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "prod", Project: "prod-project"}))
+
+	// A process refreshing the server's token reads the config
+	refreshConf, err := readConf()
+	assert.NoError(t, err)
+
+	// Meanwhile, another process switches the current context, modifies a context and adds a new one
+	assert.NoError(t, UseContext("prod"))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "dev", Project: "new-dev-project"}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "staging", ServerId: "first"}))
+
+	refreshConf.Servers[0].AccessToken = "new-token1"
+	assert.NoError(t, saveConfig(refreshConf))
+
+	conf, err := readConf()
+	assert.NoError(t, err)
+	assert.Equal(t, "new-token1", conf.Servers[0].AccessToken)
+	assert.Equal(t, "prod", conf.CurrentContext)
+	assert.Equal(t, []*ContextDetails{
+		{Name: "dev", Project: "new-dev-project"},
+		{Name: "prod", Project: "prod-project"},
+		{Name: "staging", ServerId: "first"},
+	}, conf.Contexts)
+}
+
+func TestMergeContexts(t *testing.T) {
+	baseline := []ContextDetails{{Name: "first"}, {Name: "second"}, {Name: "third"}}
+	// We modified the first context and removed the second, while they modified the second, removed the third and added a fourth.
+	ours := []*ContextDetails{{Name: "first", Project: "ours"}, {Name: "third"}}
+	theirs := []*ContextDetails{{Name: "first"}, {Name: "second", Project: "theirs"}, {Name: "fourth"}}
+	merged := mergeContexts(baseline, ours, theirs)
+	assert.Equal(t, []*ContextDetails{{Name: "first", Project: "ours"}, {Name: "fourth"}}, merged)
+}
+
+func TestMergeServersSingleDefault(t *testing.T) {
+	baseline := []ServerDetails{{ServerId: "first", IsDefault: true}, {ServerId: "second"}}
+	// We set the second server as default, while they added a new default server.
+	ours := []*ServerDetails{{ServerId: "first"}, {ServerId: "second", IsDefault: true}}
+	theirs := []*ServerDetails{{ServerId: "first"}, {ServerId: "second"}, {ServerId: "third", IsDefault: true}}
+	merged := mergeServers(baseline, ours, theirs)
+	assert.Len(t, merged, 3)
+	assert.False(t, merged[0].IsDefault)
+	assert.True(t, merged[1].IsDefault)
+	assert.False(t, merged[2].IsDefault)
+}
+
+func TestWriteFileAtomically(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "file")
+	assert.NoError(t, writeFileAtomically(path, []byte("first")))
+	assert.NoError(t, writeFileAtomically(path, []byte("second")))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	// Ensure no temporary files are left behind
+	files, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
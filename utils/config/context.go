@@ -0,0 +1,211 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The file holding the name of the context to use in a directory and its subdirectories.
+var contextFilePath = filepath.Join(".jfrog", "context")
+
+// A named profile, bundling the defaults used by the commands.
+type ContextDetails struct {
+	Name string `json:"name,omitempty"`
+	// The server used when no server ID is provided.
+	ServerId string `json:"serverId,omitempty"`
+	// The JFrog project key used when no project is provided.
+	Project string `json:"project,omitempty"`
+	// The build name used when no build name is provided.
+	BuildName string `json:"buildName,omitempty"`
+}
+
+// Returns all the configured contexts.
+func GetAllContexts() ([]*ContextDetails, error) {
+	conf, err := readConf()
+	if err != nil {
+		return nil, err
+	}
+	if conf.Contexts == nil {
+		return make([]*ContextDetails, 0), nil
+	}
+	return conf.Contexts, nil
+}
+
+// Adds the context, or replaces an existing context with the same name.
+func SaveContext(context *ContextDetails) error {
+	if context.Name == "" {
+		return errorutils.CheckErrorf("a context name must be provided")
+	}
+	conf, err := readConf()
+	if err != nil {
+		return err
+	}
+	if context.ServerId != "" {
+		if _, err = getServerConfByServerId(context.ServerId, conf.Servers); err != nil {
+			return err
+		}
+	}
+	for i, existing := range conf.Contexts {
+		if existing.Name == context.Name {
+			conf.Contexts[i] = context
+			return saveConfig(conf)
+		}
+	}
+	conf.Contexts = append(conf.Contexts, context)
+	return saveConfig(conf)
+}
+
+// Removes the context. If the context is the current context, no context will be current.
+func DeleteContext(name string) error {
+	conf, err := readConf()
+	if err != nil {
+		return err
+	}
+	for i, existing := range conf.Contexts {
+		if existing.Name == name {
+			conf.Contexts = append(conf.Contexts[:i], conf.Contexts[i+1:]...)
+			if conf.CurrentContext == name {
+				conf.CurrentContext = ""
+			}
+			return saveConfig(conf)
+		}
+	}
+	return errorutils.CheckErrorf("Context '%s' does not exist.", name)
+}
+
+// Sets the current context, used when no context is selected by the JFROG_CLI_CONTEXT environment variable or by a '.jfrog/context' file.
+// An empty name unsets the current context.
+func UseContext(name string) error {
+	conf, err := readConf()
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		if _, err = getContextByName(name, conf.Contexts); err != nil {
+			return err
+		}
+	}
+	conf.CurrentContext = name
+	return saveConfig(conf)
+}
+
+// Detaches the contexts from servers which were removed, so that the default server is used by these contexts instead.
+func (config *Config) detachRemovedServersFromContexts() {
+	serverIds := make(map[string]bool)
+	for _, serverDetails := range config.Servers {
+		serverIds[serverDetails.ServerId] = true
+	}
+	for _, context := range config.Contexts {
+		if context.ServerId != "" && !serverIds[context.ServerId] {
+			log.Info("Server ID '" + context.ServerId + "' was removed. The '" + context.Name + "' context will use the default server.")
+			context.ServerId = ""
+		}
+	}
+}
+
+// Returns the active context, or nil if no context is active.
+// The active context is selected by the following order:
+// 1. The JFROG_CLI_CONTEXT environment variable.
+// 2. The '.jfrog/context' file, found in the working directory or one of its parents.
+// 3. The current context, set by UseContext.
+// The active context is cached per process, since resolving it requires reading the whole config.
+func GetActiveContext() (*ContextDetails, error) {
+	key, err := getActiveContextCacheKey()
+	if err != nil {
+		return nil, err
+	}
+	activeContextCacheMutex.Lock()
+	defer activeContextCacheMutex.Unlock()
+	if activeContextCache != nil && activeContextCache.key == key {
+		return activeContextCache.context, activeContextCache.err
+	}
+	conf, err := readConf()
+	if err != nil {
+		return nil, err
+	}
+	context, err := conf.getActiveContext()
+	activeContextCache = &activeContextCacheEntry{key: key, context: context, err: err}
+	return context, err
+}
+
+// The last active context resolved by GetActiveContext.
+// The key holds the inputs selecting the active context, other than the config itself. The cache is reset whenever the config is saved.
+type activeContextCacheEntry struct {
+	key     string
+	context *ContextDetails
+	err     error
+}
+
+var (
+	activeContextCache      *activeContextCacheEntry
+	activeContextCacheMutex sync.Mutex
+)
+
+func getActiveContextCacheKey() (string, error) {
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return strings.Join([]string{homeDir, os.Getenv(coreutils.Context), wd}, string(os.PathListSeparator)), nil
+}
+
+func resetActiveContextCache() {
+	activeContextCacheMutex.Lock()
+	defer activeContextCacheMutex.Unlock()
+	activeContextCache = nil
+}
+
+func (config *Config) getActiveContext() (*ContextDetails, error) {
+	name, err := getActiveContextName(config)
+	if err != nil || name == "" {
+		return nil, err
+	}
+	return getContextByName(name, config.Contexts)
+}
+
+func getActiveContextName(config *Config) (string, error) {
+	if name := os.Getenv(coreutils.Context); name != "" {
+		return name, nil
+	}
+	name, err := readContextFile()
+	if err != nil || name != "" {
+		return name, err
+	}
+	return config.CurrentContext, nil
+}
+
+// Reads the context name from the '.jfrog/context' file in the working directory or one of its parents.
+func readContextFile() (string, error) {
+	dir, exists, err := fileutils.FindUpstream(contextFilePath, fileutils.File)
+	if err != nil || !exists {
+		return "", err
+	}
+	path := filepath.Join(dir, contextFilePath)
+	content, err := fileutils.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(string(content))
+	log.Debug("Using context '" + name + "' from: " + path)
+	return name, nil
+}
+
+func getContextByName(name string, contexts []*ContextDetails) (*ContextDetails, error) {
+	for _, context := range contexts {
+		if context.Name == name {
+			return context, nil
+		}
+	}
+	return nil, errorutils.CheckErrorf("Context '%s' does not exist.", name)
+}
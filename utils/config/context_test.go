@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContexts(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "default-server", IsDefault: true},
+		{ServerId: "prod-server"},
+		{ServerId: "dev-server"},
+	}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "prod", ServerId: "prod-server", Project: "prod-project", BuildName: "prod-build"}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "dev", ServerId: "dev-server"}))
+	// Contexts must point to existing servers
+	assert.Error(t, SaveContext(&ContextDetails{Name: "bad", ServerId: "not-exist"}))
+
+	// No active context - the default server is used
+	assertDefaultServerId(t, "default-server")
+
+	// Current context
+	assert.NoError(t, UseContext("prod"))
+	assertDefaultServerId(t, "prod-server")
+	context, err := GetActiveContext()
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-project", context.Project)
+	assert.Equal(t, "prod-build", context.BuildName)
+
+	// Context file in a parent directory overrides the current context
+	projectDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".jfrog"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, contextFilePath), []byte("dev\n"), 0644))
+	subDir := filepath.Join(projectDir, "module", "sub")
+	assert.NoError(t, os.MkdirAll(subDir, 0755))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	chdirCallback := testsutils.ChangeDirWithCallback(t, wd, subDir)
+	defer chdirCallback()
+	assertDefaultServerId(t, "dev-server")
+
+	// Environment variable overrides the context file
+	testsutils.SetEnvAndAssert(t, coreutils.Context, "prod")
+	defer testsutils.UnSetEnvAndAssert(t, coreutils.Context)
+	assertDefaultServerId(t, "prod-server")
+
+	// An explicit server ID is always used
+	details, err := GetSpecificConfig("default-server", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "default-server", details.ServerId)
+
+	// Deleting the current context
+	assert.NoError(t, DeleteContext("prod"))
+	assert.Error(t, DeleteContext("prod"))
+	_, err = GetActiveContext()
+	assert.Error(t, err)
+	// A context which doesn't exist is ignored, and the default server is used
+	assertDefaultServerId(t, "default-server")
+	contexts, err := GetAllContexts()
+	assert.NoError(t, err)
+	assert.Len(t, contexts, 1)
+}
+
+func TestRemoveServerDetachesContexts(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "default-server", IsDefault: true}, {ServerId: "prod-server"}}))
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "prod", ServerId: "prod-server", Project: "prod-project"}))
+	assert.NoError(t, UseContext("prod"))
+
+	// Remove the server of the current context. The default server should be used instead.
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "default-server", IsDefault: true}}))
+	assertDefaultServerId(t, "default-server")
+	context, err := GetActiveContext()
+	assert.NoError(t, err)
+	assert.Equal(t, &ContextDetails{Name: "prod", Project: "prod-project"}, context)
+}
+
+func assertDefaultServerId(t *testing.T, expectedServerId string) {
+	details, err := GetSpecificConfig("", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedServerId, details.ServerId)
+	details, err = GetDefaultServerConf()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedServerId, details.ServerId)
+}
+
+func TestActiveContextCache(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "prod", Project: "prod-project"}))
+	assert.NoError(t, UseContext("prod"))
+	context, err := GetActiveContext()
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-project", context.Project)
+
+	// The active context is resolved once, without reading the config again
+	confFilePath, err := getConfFilePath()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(confFilePath))
+	context, err = GetActiveContext()
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-project", context.Project)
+
+	// Saving the config resets the cache
+	assert.NoError(t, SaveContext(&ContextDetails{Name: "dev"}))
+	context, err = GetActiveContext()
+	assert.NoError(t, err)
+	assert.Nil(t, context)
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+type ConfigIssueSeverity string
+
+const (
+	// The server configuration can't be used as is.
+	ConfigIssueError ConfigIssueSeverity = "error"
+	// The server configuration can be used, but some commands might fail or behave unexpectedly.
+	ConfigIssueWarning ConfigIssueSeverity = "warning"
+)
+
+// The names of the checks performed by DiagnoseServers.
+const (
+	ServerIdCheck        = "server-id"
+	DefaultServerCheck   = "default-server"
+	UrlCheck             = "url"
+	AuthCheck            = "auth"
+	AccessTokenCheck     = "access-token"
+	RefreshIntervalCheck = "refresh-interval"
+	ClientCertCheck      = "client-cert"
+	SshKeyCheck          = "ssh-key"
+	OidcCheck            = "oidc"
+)
+
+// Access tokens expiring within this period are reported.
+const accessTokenExpiryWarningPeriod = 24 * time.Hour
+
+// A problem found in a server configuration.
+type ConfigIssue struct {
+	ServerId string              `json:"serverId,omitempty"`
+	Severity ConfigIssueSeverity `json:"severity"`
+	Check    string              `json:"check"`
+	Message  string              `json:"message"`
+}
+
+// Inspects the server configurations locally, without sending any request, and returns the problems found.
+func DiagnoseServers(servers []*ServerDetails) []ConfigIssue {
+	var issues []ConfigIssue
+	serverIds := make(map[string]bool)
+	defaults := 0
+	for _, serverDetails := range servers {
+		if serverDetails.IsDefault {
+			defaults++
+		}
+		if serverIds[serverDetails.ServerId] {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, ServerIdCheck, "the server ID is used by more than one server configuration"))
+		}
+		serverIds[serverDetails.ServerId] = true
+		issues = append(issues, DiagnoseServer(serverDetails)...)
+	}
+	if len(servers) > 0 && defaults != 1 {
+		issues = append(issues, ConfigIssue{Severity: ConfigIssueWarning, Check: DefaultServerCheck, Message: fmt.Sprintf("expected a single default server, but found %d", defaults)})
+	}
+	return issues
+}
+
+// Inspects a single server configuration locally, without sending any request, and returns the problems found.
+func DiagnoseServer(serverDetails *ServerDetails) []ConfigIssue {
+	var issues []ConfigIssue
+	if serverDetails.ServerId == "" {
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, ServerIdCheck, "the server ID is missing"))
+	}
+	issues = append(issues, diagnoseUrls(serverDetails)...)
+	issues = append(issues, diagnoseAuth(serverDetails)...)
+	issues = append(issues, diagnoseAccessToken(serverDetails)...)
+	issues = append(issues, diagnoseFiles(serverDetails)...)
+	return issues
+}
+
+func diagnoseUrls(serverDetails *ServerDetails) (issues []ConfigIssue) {
+	if serverDetails.Url == "" && serverDetails.ArtifactoryUrl == "" {
+		return append(issues, newConfigIssue(serverDetails, ConfigIssueError, UrlCheck, "both the JFrog Platform URL and the Artifactory URL are missing"))
+	}
+	if fileutils.IsSshUrl(serverDetails.Url) || fileutils.IsSshUrl(serverDetails.ArtifactoryUrl) {
+		return
+	}
+	if serverDetails.Url == "" {
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, UrlCheck, "the JFrog Platform URL is missing. Commands using the JFrog Platform services, such as Access, will fail"))
+	}
+	platformUrl := clientutils.AddTrailingSlashIfNeeded(serverDetails.Url)
+	for _, serviceUrl := range []struct{ name, url string }{
+		{"JFrog Platform", serverDetails.Url},
+		{"Artifactory", serverDetails.ArtifactoryUrl},
+		{"Distribution", serverDetails.DistributionUrl},
+		{"Xray", serverDetails.XrayUrl},
+		{"Mission Control", serverDetails.MissionControlUrl},
+		{"Pipelines", serverDetails.PipelinesUrl},
+		{"Access", serverDetails.AccessUrl},
+	} {
+		if serviceUrl.url == "" {
+			continue
+		}
+		parsedUrl, err := url.Parse(serviceUrl.url)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, UrlCheck, fmt.Sprintf("the %s URL '%s' is not a valid HTTP URL", serviceUrl.name, serviceUrl.url)))
+			continue
+		}
+		if serverDetails.Url != "" && !strings.HasPrefix(clientutils.AddTrailingSlashIfNeeded(serviceUrl.url), platformUrl) {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, UrlCheck, fmt.Sprintf("the %s URL '%s' doesn't match the JFrog Platform URL '%s'", serviceUrl.name, serviceUrl.url, serverDetails.Url)))
+		}
+	}
+	return
+}
+
+func diagnoseAuth(serverDetails *ServerDetails) (issues []ConfigIssue) {
+	authMethods := 0
+	for _, configured := range []bool{
+		serverDetails.User != "" && serverDetails.Password != "",
+		serverDetails.AccessToken != "" && serverDetails.ArtifactoryRefreshToken == "" && serverDetails.OidcProviderName == "",
+		serverDetails.SshKeyPath != "",
+		serverDetails.OidcProviderName != "",
+	} {
+		if configured {
+			authMethods++
+		}
+	}
+	hasClientCert := serverDetails.ClientCertPath != "" && serverDetails.ClientCertKeyPath != ""
+	switch {
+	case authMethods > 1:
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, AuthCheck, "more than one authentication method is configured"))
+	case authMethods == 0 && serverDetails.AccessToken == "" && !hasClientCert && !fileutils.IsSshUrl(serverDetails.ArtifactoryUrl):
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, AuthCheck, "no credentials are configured. Requests will be sent anonymously"))
+	}
+	if serverDetails.Password != "" && serverDetails.User == "" {
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, AuthCheck, "a password is configured without a username"))
+	}
+	if serverDetails.ArtifactoryTokenRefreshInterval > 0 {
+		if serverDetails.User == "" || serverDetails.Password == "" {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, RefreshIntervalCheck, "a token refresh interval is configured, but refreshable tokens can only be created using a username and a password"))
+		} else if serverDetails.AccessToken != "" && serverDetails.ArtifactoryRefreshToken == "" {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, RefreshIntervalCheck, "a token refresh interval is configured for a non-refreshable access token"))
+		}
+	}
+	if serverDetails.OidcProviderName != "" && serverDetails.OidcTokenFile == "" {
+		envVar := serverDetails.OidcTokenEnvVar
+		if envVar == "" {
+			envVar = coreutils.OidcIdToken
+		}
+		if os.Getenv(envVar) == "" {
+			issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, OidcCheck, fmt.Sprintf("the '%s' environment variable, holding the OIDC ID token, is not set", envVar)))
+		}
+	}
+	return
+}
+
+func diagnoseAccessToken(serverDetails *ServerDetails) (issues []ConfigIssue) {
+	token := serverDetails.AccessToken
+	if token == "" {
+		return
+	}
+	if httpclient.IsApiKey(token) {
+		return append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, AccessTokenCheck, "the access token is an API key, and should be used as a password"))
+	}
+	// Reference tokens can't be decoded locally.
+	if strings.Count(token, ".") != 2 {
+		return
+	}
+	expiry, err := auth.ExtractExpiryFromAccessToken(token)
+	if err != nil {
+		return append(issues, newConfigIssue(serverDetails, ConfigIssueError, AccessTokenCheck, "the access token couldn't be decoded: "+err.Error()))
+	}
+	// Tokens without an expiration time never expire.
+	if expiry <= 0 {
+		return
+	}
+	// Refreshable and exchanged tokens are renewed automatically when they expire.
+	renewable := serverDetails.RefreshToken != "" || serverDetails.ArtifactoryRefreshToken != "" || serverDetails.OidcProviderName != ""
+	timeLeft, err := auth.GetTokenMinutesLeft(token)
+	switch {
+	case err != nil || renewable:
+	case timeLeft <= 0:
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, AccessTokenCheck, "the access token has expired"))
+	case time.Duration(timeLeft)*time.Minute < accessTokenExpiryWarningPeriod:
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueWarning, AccessTokenCheck, fmt.Sprintf("the access token expires in %d minutes", timeLeft)))
+	}
+	return
+}
+
+func diagnoseFiles(serverDetails *ServerDetails) (issues []ConfigIssue) {
+	if (serverDetails.ClientCertPath == "") != (serverDetails.ClientCertKeyPath == "") {
+		issues = append(issues, newConfigIssue(serverDetails, ConfigIssueError, ClientCertCheck, "both the client certificate path and the client certificate key path should be configured"))
+	}
+	for _, file := range []struct {
+		check, description, path string
+		severity                 ConfigIssueSeverity
+	}{
+		{ClientCertCheck, "client certificate", serverDetails.ClientCertPath, ConfigIssueError},
+		{ClientCertCheck, "client certificate key", serverDetails.ClientCertKeyPath, ConfigIssueError},
+		{SshKeyCheck, "SSH key", serverDetails.SshKeyPath, ConfigIssueWarning},
+		{OidcCheck, "OIDC ID token", serverDetails.OidcTokenFile, ConfigIssueError},
+	} {
+		if file.path == "" {
+			continue
+		}
+		exists, err := fileutils.IsFileExists(clientutils.ReplaceTildeWithUserHome(file.path), false)
+		if err != nil || !exists {
+			issues = append(issues, newConfigIssue(serverDetails, file.severity, file.check, fmt.Sprintf("the %s file '%s' does not exist", file.description, file.path)))
+		}
+	}
+	return
+}
+
+func newConfigIssue(serverDetails *ServerDetails, severity ConfigIssueSeverity, check, message string) ConfigIssue {
+	return ConfigIssue{ServerId: serverDetails.ServerId, Severity: severity, Check: check, Message: message}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnoseServer(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "cert.pem")
+	assert.NoError(t, os.WriteFile(certPath, []byte("cert"), 0600))
+
+	testCases := []struct {
+		name           string
+		serverDetails  *ServerDetails
+		expectedChecks map[string]ConfigIssueSeverity
+	}{
+		{"valid", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", User: "admin", Password: "password"}, map[string]ConfigIssueSeverity{}},
+		{"missingUrls", &ServerDetails{ServerId: "server", AccessToken: "token"}, map[string]ConfigIssueSeverity{UrlCheck: ConfigIssueError}},
+		{"missingPlatformUrl", &ServerDetails{ServerId: "server", ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"}, map[string]ConfigIssueSeverity{UrlCheck: ConfigIssueWarning}},
+		{"mismatchingUrls", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", XrayUrl: "https://other.jfrog.io/xray/", AccessToken: "token"}, map[string]ConfigIssueSeverity{UrlCheck: ConfigIssueWarning}},
+		{"invalidUrl", &ServerDetails{ServerId: "server", Url: "acme.jfrog.io", AccessToken: "token"}, map[string]ConfigIssueSeverity{UrlCheck: ConfigIssueError}},
+		{"noCredentials", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/"}, map[string]ConfigIssueSeverity{AuthCheck: ConfigIssueWarning}},
+		{"multipleAuthMethods", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", User: "admin", Password: "password", AccessToken: "token"}, map[string]ConfigIssueSeverity{AuthCheck: ConfigIssueError}},
+		{"expiredToken", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: createTestAccessToken(-time.Hour)}, map[string]ConfigIssueSeverity{AccessTokenCheck: ConfigIssueError}},
+		{"expiringToken", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: createTestAccessToken(time.Hour)}, map[string]ConfigIssueSeverity{AccessTokenCheck: ConfigIssueWarning}},
+		{"expiredRefreshableToken", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: createTestAccessToken(-time.Hour), RefreshToken: "refresh"}, map[string]ConfigIssueSeverity{}},
+		{"refreshIntervalOnToken", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: "token", ArtifactoryTokenRefreshInterval: 60}, map[string]ConfigIssueSeverity{RefreshIntervalCheck: ConfigIssueWarning}},
+		{"missingClientCert", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", ClientCertPath: certPath, ClientCertKeyPath: filepath.Join(t.TempDir(), "not-exist")}, map[string]ConfigIssueSeverity{ClientCertCheck: ConfigIssueError}},
+		{"partialClientCert", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: "token", ClientCertPath: certPath}, map[string]ConfigIssueSeverity{ClientCertCheck: ConfigIssueError}},
+		{"missingOidcTokenFile", &ServerDetails{ServerId: "server", Url: "https://acme.jfrog.io/", OidcProviderName: "github", OidcTokenFile: filepath.Join(t.TempDir(), "not-exist")}, map[string]ConfigIssueSeverity{OidcCheck: ConfigIssueError}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			issues := DiagnoseServer(testCase.serverDetails)
+			actualChecks := make(map[string]ConfigIssueSeverity)
+			for _, issue := range issues {
+				assert.Equal(t, "server", issue.ServerId)
+				actualChecks[issue.Check] = issue.Severity
+			}
+			assert.Equal(t, testCase.expectedChecks, actualChecks, issues)
+		})
+	}
+}
+
+func TestDiagnoseServers(t *testing.T) {
+	servers := []*ServerDetails{
+		{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: "token"},
+		{ServerId: "server", Url: "https://acme.jfrog.io/", AccessToken: "token"},
+	}
+	issues := DiagnoseServers(servers)
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, ServerIdCheck, issues[0].Check)
+		assert.Equal(t, DefaultServerCheck, issues[1].Check)
+	}
+}
@@ -68,9 +68,13 @@ func updateEncryptionIfNeeded(config *Config) error {
 }
 
 // Encrypt/Decrypt all secrets in the provided config, with the provided master key.
+// Secrets kept in an external secret store are not handled.
 func handleSecrets(config *Config, handler secretHandler, key string) error {
 	var err error
 	for _, serverDetails := range config.Servers {
+		if serverDetails.SecretsRef != "" {
+			continue
+		}
 		serverDetails.Password, err = handler(serverDetails.Password, key)
 		if err != nil {
 			return err
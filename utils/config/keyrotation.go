@@ -0,0 +1,190 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	keyRotationBackupPrefix = "key-rotation-"
+	securityConfVersion     = "1"
+)
+
+// Re-encrypts all the config secrets with the new master key.
+// The config is decrypted with the current master key, taken from the JFROG_CLI_ENCRYPTION_KEY environment variable or from the security configuration file.
+// If the current key was taken from the security configuration file, the file is updated with the new key.
+// Otherwise, the JFROG_CLI_ENCRYPTION_KEY environment variable should be updated by the caller.
+// Before replacing any file, a backup is created. Returns the path to the backup, which can be used to roll back the rotation using RestoreKeyRotationBackup.
+func RotateEncryptionKey(newKey string) (backupPath string, err error) {
+	if len(newKey) != masterKeyLength {
+		return "", errorutils.CheckErrorf("wrong length for the new master key. Key should have a length of exactly: %d bytes", masterKeyLength)
+	}
+	oldKey, err := getEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+	if oldKey == "" {
+		return "", errorutils.CheckErrorf("cannot rotate the master key: security configuration file was not found or the '%s' environment variable was not configured", coreutils.EncryptionKey)
+	}
+	if oldKey == newKey {
+		return "", errorutils.CheckErrorf("cannot rotate the master key: the new master key is identical to the current one")
+	}
+	_, keyFromEnv := os.LookupEnv(coreutils.EncryptionKey)
+
+	// Decrypt the config with the current key.
+	conf, err := readConf()
+	if err != nil {
+		return "", err
+	}
+	// The rest of the rotation is done under the config write lock, so that no other process writes the config while the key is replaced.
+	// Modifications made by other processes since the config was read are merged into it before it's encrypted with the new key.
+	err = withConfigWriteLock(func() (err error) {
+		backupPath, err = createKeyRotationBackup()
+		if err != nil {
+			return
+		}
+		log.Debug("Created a backup of the config and the security configuration at: " + backupPath)
+		defer func() {
+			if err == nil {
+				return
+			}
+			log.Debug("Master key rotation failed, restoring the backup from: " + backupPath)
+			if restoreErr := restoreKeyRotationBackupLocked(backupPath); restoreErr != nil {
+				log.Error("Couldn't restore the config from the backup: " + restoreErr.Error())
+			}
+		}()
+		if err = writeConfigFileLocked(conf, func() ([]byte, error) {
+			return encryptConfigContent(conf, newKey)
+		}); err != nil {
+			return
+		}
+		if keyFromEnv {
+			log.Warn("The config was encrypted with the new master key. Make sure to update the " + coreutils.EncryptionKey + " environment variable.")
+			return
+		}
+		return saveSecurityConf(&SecurityConf{Version: securityConfVersion, MasterKey: newKey})
+	})
+	if err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// Encrypts the config secrets with the provided key and returns the config file content.
+func encryptConfigContent(config *Config, key string) ([]byte, error) {
+	cloneConfig, err := config.Clone()
+	if err != nil {
+		return nil, err
+	}
+	// The secrets kept in an external secret store are not affected by the master key, and remain there.
+	cloneConfig.clearExternalSecrets()
+	cloneConfig.Enc = true
+	if err = handleSecrets(cloneConfig, encrypt, key); err != nil {
+		return nil, err
+	}
+	return cloneConfig.getContent()
+}
+
+func saveSecurityConf(securityConf *SecurityConf) error {
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return err
+	}
+	if err = fileutils.CreateDirIfNotExist(filepath.Dir(secFile)); err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(securityConf)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return writeFileAtomically(secFile, content)
+}
+
+// Copies the config file and the security configuration file to a new directory under the backup dir.
+func createKeyRotationBackup() (backupPath string, err error) {
+	backupDir, err := coreutils.GetJfrogBackupDir()
+	if err != nil {
+		return
+	}
+	backupPath = filepath.Join(backupDir, keyRotationBackupPrefix+strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err = fileutils.CreateDirIfNotExist(backupPath); err != nil {
+		return
+	}
+	confFilePath, err := getConfFilePath()
+	if err != nil {
+		return
+	}
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return
+	}
+	for _, path := range []string{confFilePath, secFile} {
+		var exists bool
+		exists, err = fileutils.IsFileExists(path, false)
+		if err != nil {
+			return
+		}
+		if !exists {
+			continue
+		}
+		if err = fileutils.CopyFile(backupPath, path); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Restores the config file and the security configuration file from a backup created by RotateEncryptionKey.
+// If the security configuration file didn't exist when the backup was created, it is removed.
+func RestoreKeyRotationBackup(backupPath string) error {
+	exists, err := fileutils.IsDirExists(backupPath, false)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errorutils.CheckErrorf("the backup directory '%s' does not exist", backupPath)
+	}
+	return withConfigWriteLock(func() error {
+		return restoreKeyRotationBackupLocked(backupPath)
+	})
+}
+
+func restoreKeyRotationBackupLocked(backupPath string) error {
+	confFilePath, err := getConfFilePath()
+	if err != nil {
+		return err
+	}
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return err
+	}
+	for _, path := range []string{confFilePath, secFile} {
+		backupFile := filepath.Join(backupPath, filepath.Base(path))
+		exists, err := fileutils.IsFileExists(backupFile, false)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errorutils.CheckError(err)
+			}
+			continue
+		}
+		content, err := fileutils.ReadFile(backupFile)
+		if err != nil {
+			return err
+		}
+		if err = writeFileAtomically(path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
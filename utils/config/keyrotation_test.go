@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	configtests "github.com/jfrog/jfrog-cli-core/v2/utils/config/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const rotatedMasterKey = "anotherkeywithlengthofexactly32!"
+
+func TestRotateEncryptionKey(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+
+	assert.NoError(t, saveConfig(createEncryptionTestConfig()))
+	expectedConfig := createEncryptionTestConfig()
+	encryptedConfig := readConfFromFile(t)
+
+	backupPath, err := RotateEncryptionKey(rotatedMasterKey)
+	assert.NoError(t, err)
+	assert.DirExists(t, backupPath)
+
+	// Ensure the security configuration file holds the new key, and the secrets were re-encrypted
+	key, err := getEncryptionKeyFromSecurityConfFile()
+	assert.NoError(t, err)
+	assert.Equal(t, rotatedMasterKey, key)
+	rotatedConfig := readConfFromFile(t)
+	assert.True(t, rotatedConfig.Enc)
+	assert.NotEqual(t, encryptedConfig.Servers[0].Password, rotatedConfig.Servers[0].Password)
+	actualConfig, err := readConf()
+	assert.NoError(t, err)
+	verifyEncryptionStatus(t, expectedConfig, actualConfig, false)
+
+	// Roll back and ensure the config can be decrypted with the old key
+	assert.NoError(t, RestoreKeyRotationBackup(backupPath))
+	key, err = getEncryptionKeyFromSecurityConfFile()
+	assert.NoError(t, err)
+	assert.NotEqual(t, rotatedMasterKey, key)
+	actualConfig, err = readConf()
+	assert.NoError(t, err)
+	verifyEncryptionStatus(t, expectedConfig, actualConfig, false)
+}
+
+func TestRotateEncryptionKeyEnvVar(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	assert.NoError(t, os.Setenv(coreutils.EncryptionKey, "randomkeywithlengthofexactly32!!"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv(coreutils.EncryptionKey))
+	}()
+	assert.NoError(t, saveConfig(createEncryptionTestConfig()))
+
+	_, err := RotateEncryptionKey(rotatedMasterKey)
+	assert.NoError(t, err)
+
+	// The security configuration file should not be created, and the config should be decrypted with the new key
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, secFile)
+	assert.NoError(t, os.Setenv(coreutils.EncryptionKey, rotatedMasterKey))
+	actualConfig, err := readConf()
+	assert.NoError(t, err)
+	verifyEncryptionStatus(t, createEncryptionTestConfig(), actualConfig, false)
+}
+
+func TestRotateEncryptionKeyErrors(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+
+	// Wrong key length
+	_, err := RotateEncryptionKey("short")
+	assert.Error(t, err)
+	// Identical key
+	_, err = RotateEncryptionKey("randomkeywithlengthofexactly32!!")
+	assert.Error(t, err)
+}
+
+func TestRotateEncryptionKeyExternalSecretStore(t *testing.T) {
+	useMemorySecretStore(t)
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+
+	expectedConfig := createEncryptionTestConfig()
+	assert.NoError(t, saveConfig(createEncryptionTestConfig()))
+	assert.NoError(t, SetSecretStore(&SecretStoreDetails{Type: memorySecretStoreType}))
+	_, err := RotateEncryptionKey(rotatedMasterKey)
+	assert.NoError(t, err)
+
+	// The secrets should remain in the secret store, and not be written to the config file.
+	rotatedConfig := readConfFromFile(t)
+	assert.Equal(t, &ServerSecrets{}, rotatedConfig.Servers[0].getSecrets())
+	secrets, err := processMemorySecretStore.Get("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConfig.Servers[0].getSecrets(), secrets)
+	actualConfig, err := readConf()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConfig.Servers[0].getSecrets(), actualConfig.Servers[0].getSecrets())
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	oidcTokenExchangeApi       = "api/v1/oidc/token"
+	oidcTokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	oidcIdTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// The access tokens acquired by OIDC token exchanges in the current process, mapped by the platform URL and the provider name.
+// Guarded by the token refresh mutex.
+var oidcAccessTokens = make(map[string]string)
+
+type oidcTokenExchangeRequest struct {
+	GrantType        string `json:"grant_type"`
+	SubjectTokenType string `json:"subject_token_type"`
+	SubjectToken     string `json:"subject_token"`
+	ProviderName     string `json:"provider_name"`
+}
+
+// Returns a pre-request interceptor, which exchanges the CI provided OIDC ID token for a short-lived access token
+// whenever the current access token is missing or about to expire.
+func newOidcTokenExchangePreRequestInterceptor(serverDetails *ServerDetails) auth.ServiceDetailsPreRequestFunc {
+	exchangeDetails := *serverDetails
+	return func(fields *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+		return oidcTokenExchangePreRequestInterceptor(&exchangeDetails, fields, httpClientDetails)
+	}
+}
+
+func oidcTokenExchangePreRequestInterceptor(serverDetails *ServerDetails, fields *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+	if isAccessTokenValid(httpClientDetails.AccessToken) {
+		return nil
+	}
+	// Lock to make sure only one thread is trying to exchange
+	mutex.Lock()
+	defer mutex.Unlock()
+	// Exchange only if a new token wasn't acquired (by another thread) while waiting at mutex.
+	if !isAccessTokenValid(fields.AccessToken) {
+		newAccessToken, err := oidcTokenExchangeHandler(serverDetails)
+		if err != nil {
+			return err
+		}
+		fields.AccessToken = newAccessToken
+	}
+	// Copy new token from the mutual struct CommonConfigFields to the private struct in httpClientDetails
+	httpClientDetails.AccessToken = fields.AccessToken
+	return nil
+}
+
+// Returns true if the token is a JWT access token, which isn't about to expire.
+func isAccessTokenValid(token string) bool {
+	if token == "" {
+		return false
+	}
+	timeLeft, err := auth.GetTokenMinutesLeft(token)
+	return err == nil && timeLeft > auth.RefreshBeforeExpiryMinutes
+}
+
+func oidcTokenExchangeHandler(serverDetails *ServerDetails) (newAccessToken string, err error) {
+	cacheKey := clientutils.AddTrailingSlashIfNeeded(serverDetails.Url) + serverDetails.OidcProviderName
+	if cachedToken := oidcAccessTokens[cacheKey]; isAccessTokenValid(cachedToken) {
+		return cachedToken, nil
+	}
+	// Lock config to prevent access from different processes
+	lockDirPath, err := coreutils.GetJfrogConfigLockDir()
+	if err != nil {
+		return
+	}
+	unlockFunc, err := lock.CreateLock(lockDirPath)
+	// Defer the lockFile.Unlock() function before throwing a possible error to avoid deadlock situations.
+	defer func() {
+		e := unlockFunc()
+		if err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+
+	// If the server is configured, the access token is cached in the config, so that following CLI runs can reuse it.
+	configuredServer, err := getConfiguredOidcServer(serverDetails)
+	if err != nil {
+		return
+	}
+	if configuredServer != nil && isAccessTokenValid(configuredServer.AccessToken) {
+		log.Debug("Fetched OIDC access token from config.")
+		oidcAccessTokens[cacheKey] = configuredServer.AccessToken
+		return configuredServer.AccessToken, nil
+	}
+
+	log.Debug("Exchanging OIDC ID token for an access token...")
+	tokenResponse, err := exchangeOidcToken(serverDetails)
+	if err != nil {
+		return
+	}
+	log.Debug("OIDC token exchanged successfully.")
+	oidcAccessTokens[cacheKey] = tokenResponse.AccessToken
+	if configuredServer != nil {
+		err = writeNewArtifactoryTokens(configuredServer, configuredServer.ServerId, tokenResponse.AccessToken, "")
+	}
+	return tokenResponse.AccessToken, err
+}
+
+// Returns the configured server matching the server details, or nil if the server isn't configured with the same OIDC provider.
+func getConfiguredOidcServer(serverDetails *ServerDetails) (*ServerDetails, error) {
+	if serverDetails.ServerId == "" {
+		return nil, nil
+	}
+	conf, err := readConf()
+	if err != nil {
+		return nil, err
+	}
+	for _, configuredServer := range conf.Servers {
+		if configuredServer.ServerId == serverDetails.ServerId && configuredServer.OidcProviderName == serverDetails.OidcProviderName {
+			return configuredServer, nil
+		}
+	}
+	return nil, nil
+}
+
+// Exchanges the OIDC ID token for an access token, using the Access token exchange API.
+func exchangeOidcToken(serverDetails *ServerDetails) (auth.CreateTokenResponseData, error) {
+	tokenInfo := auth.CreateTokenResponseData{}
+	if serverDetails.Url == "" {
+		return tokenInfo, errorutils.CheckErrorf("the JFrog Platform URL is required for the OIDC token exchange")
+	}
+	idToken, err := readOidcIdToken(serverDetails)
+	if err != nil {
+		return tokenInfo, err
+	}
+	// Creating accessTokens service manager without credentials, to avoid a recursive token exchange.
+	noCredServerDetails := new(ServerDetails)
+	noCredServerDetails.Url = serverDetails.Url
+	noCredServerDetails.ClientCertPath = serverDetails.ClientCertPath
+	noCredServerDetails.ClientCertKeyPath = serverDetails.ClientCertKeyPath
+	noCredServerDetails.ServerId = serverDetails.ServerId
+	noCredServerDetails.InsecureTls = serverDetails.InsecureTls
+	servicesManager, err := createAccessTokensServiceManager(noCredServerDetails)
+	if err != nil {
+		return tokenInfo, err
+	}
+
+	requestContent, err := json.Marshal(&oidcTokenExchangeRequest{
+		GrantType:        oidcTokenExchangeGrantType,
+		SubjectTokenType: oidcIdTokenType,
+		SubjectToken:     idToken,
+		ProviderName:     serverDetails.OidcProviderName,
+	})
+	if err != nil {
+		return tokenInfo, errorutils.CheckError(err)
+	}
+	httpDetails := httputils.HttpClientDetails{}
+	utils.SetContentType("application/json", &httpDetails.Headers)
+	url := clientutils.AddTrailingSlashIfNeeded(serverDetails.Url) + "access/" + oidcTokenExchangeApi
+	resp, body, err := servicesManager.Client().SendPost(url, requestContent, &httpDetails)
+	if err != nil {
+		return tokenInfo, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return tokenInfo, err
+	}
+	if err = json.Unmarshal(body, &tokenInfo); err != nil {
+		return tokenInfo, errorutils.CheckError(err)
+	}
+	if tokenInfo.AccessToken == "" {
+		return tokenInfo, errorutils.CheckErrorf("the OIDC token exchange response doesn't contain an access token")
+	}
+	return tokenInfo, nil
+}
+
+// Reads the OIDC ID token from the configured file, or from the configured environment variable.
+// If neither is configured, the token is read from the JFROG_CLI_OIDC_ID_TOKEN environment variable.
+func readOidcIdToken(serverDetails *ServerDetails) (string, error) {
+	if serverDetails.OidcTokenFile != "" {
+		content, err := fileutils.ReadFile(serverDetails.OidcTokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(content)), nil
+	}
+	envVar := serverDetails.OidcTokenEnvVar
+	if envVar == "" {
+		envVar = coreutils.OidcIdToken
+	}
+	idToken := strings.TrimSpace(os.Getenv(envVar))
+	if idToken == "" {
+		return "", errorutils.CheckErrorf("the OIDC ID token was not found. Make sure the '%s' environment variable is set", envVar)
+	}
+	return idToken, nil
+}
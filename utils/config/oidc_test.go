@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+const oidcTestTokenEnvVar = "JFROG_CLI_TEST_OIDC_ID_TOKEN"
+
+func TestOidcTokenExchange(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+	defer clearOidcAccessTokensCache()
+
+	exchanges := 0
+	expiresIn := time.Hour
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/access/"+oidcTokenExchangeApi, r.URL.Path)
+		request := &oidcTokenExchangeRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		assert.Equal(t, oidcTokenExchangeGrantType, request.GrantType)
+		assert.Equal(t, oidcIdTokenType, request.SubjectTokenType)
+		assert.Equal(t, "id-token", request.SubjectToken)
+		assert.Equal(t, "github", request.ProviderName)
+		exchanges++
+		_, err := fmt.Fprintf(w, `{"access_token":"%s","expires_in":3600}`, createTestAccessToken(expiresIn))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	testsutils.SetEnvAndAssert(t, oidcTestTokenEnvVar, "id-token")
+	defer testsutils.UnSetEnvAndAssert(t, oidcTestTokenEnvVar)
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "oidc", Url: ts.URL + "/", ArtifactoryUrl: ts.URL + "/artifactory/", OidcProviderName: "github", OidcTokenEnvVar: oidcTestTokenEnvVar, IsDefault: true}}))
+
+	// The first request exchanges the ID token and caches the access token in the config
+	accessToken := runPreRequestFunctions(t)
+	assert.NotEmpty(t, accessToken)
+	assert.Equal(t, 1, exchanges)
+	serverDetails, err := GetSpecificConfig("oidc", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, accessToken, serverDetails.AccessToken)
+
+	// Following runs reuse the cached access token
+	clearOidcAccessTokensCache()
+	assert.Equal(t, accessToken, runPreRequestFunctions(t))
+	assert.Equal(t, 1, exchanges)
+
+	// An access token which is about to expire is exchanged again
+	serverDetails.AccessToken = createTestAccessToken(time.Minute)
+	assert.NoError(t, SaveServersConf([]*ServerDetails{serverDetails}))
+	clearOidcAccessTokensCache()
+	assert.NotEqual(t, serverDetails.AccessToken, runPreRequestFunctions(t))
+	assert.Equal(t, 2, exchanges)
+}
+
+func TestReadOidcIdToken(t *testing.T) {
+	// Default environment variable
+	_, err := readOidcIdToken(&ServerDetails{})
+	assert.Error(t, err)
+	testsutils.SetEnvAndAssert(t, oidcTestTokenEnvVar, "env-token")
+	defer testsutils.UnSetEnvAndAssert(t, oidcTestTokenEnvVar)
+	idToken, err := readOidcIdToken(&ServerDetails{OidcTokenEnvVar: oidcTestTokenEnvVar})
+	assert.NoError(t, err)
+	assert.Equal(t, "env-token", idToken)
+
+	// The token file takes precedence over the environment variable
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0600))
+	idToken, err = readOidcIdToken(&ServerDetails{OidcTokenFile: tokenFile, OidcTokenEnvVar: oidcTestTokenEnvVar})
+	assert.NoError(t, err)
+	assert.Equal(t, "file-token", idToken)
+}
+
+func runPreRequestFunctions(t *testing.T) string {
+	serverDetails, err := GetSpecificConfig("oidc", false, false)
+	assert.NoError(t, err)
+	artAuth, err := serverDetails.CreateArtAuthConfig()
+	assert.NoError(t, err)
+	httpClientDetails := artAuth.CreateHttpClientDetails()
+	assert.NoError(t, artAuth.RunPreRequestFunctions(&httpClientDetails))
+	return httpClientDetails.AccessToken
+}
+
+func createTestAccessToken(expiresIn time.Duration) string {
+	expiry := time.Now().Add(expiresIn)
+	payload := fmt.Sprintf(`{"sub":"jfrt@test/users/oidc","exp":%d,"iat":%d,"jti":"%d"}`, expiry.Unix(), expiry.Add(-2*time.Hour).Unix(), time.Now().UnixNano())
+	return "header." + base64.RawStdEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func clearOidcAccessTokensCache() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	oidcAccessTokens = make(map[string]string)
+}
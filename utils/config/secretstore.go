@@ -0,0 +1,348 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// Secrets are kept inside the config file, optionally encrypted with the master key. This is the default.
+	FileSecretStoreType = "file"
+	// Secrets are kept by an external credential helper executable, speaking JSON over stdin/stdout.
+	CommandSecretStoreType = "command"
+)
+
+const (
+	secretHelperGetAction   = "get"
+	secretHelperStoreAction = "store"
+	secretHelperEraseAction = "erase"
+)
+
+// The secret store backend configured in the config file.
+type SecretStoreDetails struct {
+	Type string `json:"type,omitempty"`
+	// The credential helper executable and its arguments. Used by the command secret store.
+	Command string `json:"command,omitempty"`
+}
+
+// The secrets of a single server configuration.
+type ServerSecrets struct {
+	Password                string `json:"password,omitempty"`
+	AccessToken             string `json:"accessToken,omitempty"`
+	SshPassphrase           string `json:"sshPassphrase,omitempty"`
+	RefreshToken            string `json:"refreshToken,omitempty"`
+	ArtifactoryRefreshToken string `json:"artifactoryRefreshToken,omitempty"`
+}
+
+// SecretStore is the backend holding the servers secrets.
+// Secrets are stored by a reference, which is saved in the server configuration instead of the secrets themselves.
+type SecretStore interface {
+	// Returns the secrets stored for the reference. Returns empty secrets if the reference does not exist.
+	Get(ref string) (*ServerSecrets, error)
+	// Stores the secrets for the reference, replacing any existing secrets.
+	Store(ref string, secrets *ServerSecrets) error
+	// Removes the secrets stored for the reference.
+	Erase(ref string) error
+}
+
+// Secret stores registered by the running process, by their types.
+// A registered store is available only to the process which registered it, so a config persisting its type is rejected by other processes.
+var (
+	registeredSecretStores      = map[string]SecretStore{}
+	registeredSecretStoresMutex sync.RWMutex
+)
+
+// Registers a secret store under the given type, so that it can be used by SetSecretStore and by the config of the current process.
+func RegisterSecretStore(storeType string, store SecretStore) error {
+	switch storeType {
+	case "", FileSecretStoreType, CommandSecretStoreType:
+		return errorutils.CheckErrorf("the secret store type '%s' is reserved", storeType)
+	}
+	if store == nil {
+		return errorutils.CheckErrorf("a secret store must be provided for type '%s'", storeType)
+	}
+	registeredSecretStoresMutex.Lock()
+	defer registeredSecretStoresMutex.Unlock()
+	if _, exist := registeredSecretStores[storeType]; exist {
+		return errorutils.CheckErrorf("a secret store of type '%s' is already registered", storeType)
+	}
+	registeredSecretStores[storeType] = store
+	return nil
+}
+
+// Removes the secret store registered under the given type, if exists.
+func UnregisterSecretStore(storeType string) {
+	registeredSecretStoresMutex.Lock()
+	defer registeredSecretStoresMutex.Unlock()
+	delete(registeredSecretStores, storeType)
+}
+
+func getRegisteredSecretStore(storeType string) (store SecretStore, exist bool) {
+	registeredSecretStoresMutex.RLock()
+	defer registeredSecretStoresMutex.RUnlock()
+	store, exist = registeredSecretStores[storeType]
+	return
+}
+
+// Returns the secret store for the given details.
+// A nil store is returned for the file secret store, since its secrets are handled inline by the config file.
+func newSecretStore(details *SecretStoreDetails) (SecretStore, error) {
+	if details == nil {
+		return nil, nil
+	}
+	switch details.Type {
+	case "", FileSecretStoreType:
+		return nil, nil
+	case CommandSecretStoreType:
+		return NewCommandSecretStore(details.Command)
+	default:
+		if store, exist := getRegisteredSecretStore(details.Type); exist {
+			return store, nil
+		}
+		return nil, errorutils.CheckErrorf("unsupported secret store type: '%s'", details.Type)
+	}
+}
+
+func (secretStoreDetails *SecretStoreDetails) IsExternal() bool {
+	return secretStoreDetails != nil && secretStoreDetails.Type != "" && secretStoreDetails.Type != FileSecretStoreType
+}
+
+func (secretStoreDetails *SecretStoreDetails) equals(other *SecretStoreDetails) bool {
+	if !secretStoreDetails.IsExternal() || !other.IsExternal() {
+		return secretStoreDetails.IsExternal() == other.IsExternal()
+	}
+	return *secretStoreDetails == *other
+}
+
+// Moves the secrets of all servers in the config to the configured external secret store, leaving only references in the config.
+func (config *Config) storeSecrets() error {
+	store, err := newSecretStore(config.SecretStore)
+	if err != nil {
+		return err
+	}
+	for _, serverDetails := range config.Servers {
+		if store == nil {
+			// The secrets are kept inline in the config file.
+			serverDetails.SecretsRef = ""
+			continue
+		}
+		if serverDetails.SecretsRef == "" {
+			serverDetails.SecretsRef = serverDetails.ServerId
+		}
+		if err = store.Store(serverDetails.SecretsRef, serverDetails.getSecrets()); err != nil {
+			return err
+		}
+		serverDetails.setSecrets(&ServerSecrets{})
+	}
+	return nil
+}
+
+// Clears the secrets of the servers which are kept in the configured external secret store, without modifying the store.
+func (config *Config) clearExternalSecrets() {
+	if !config.SecretStore.IsExternal() {
+		return
+	}
+	for _, serverDetails := range config.Servers {
+		if serverDetails.SecretsRef != "" {
+			serverDetails.setSecrets(&ServerSecrets{})
+		}
+	}
+}
+
+// Resolves the secrets of all servers in the config from the configured external secret store.
+func (config *Config) resolveSecrets() error {
+	store, err := newSecretStore(config.SecretStore)
+	if err != nil || store == nil {
+		return err
+	}
+	for _, serverDetails := range config.Servers {
+		if serverDetails.SecretsRef == "" {
+			continue
+		}
+		secrets, err := store.Get(serverDetails.SecretsRef)
+		if err != nil {
+			return err
+		}
+		serverDetails.setSecrets(secrets)
+	}
+	return nil
+}
+
+// Erases the secrets of servers that exist in the previous config, but not in the current one.
+func eraseRemovedSecrets(previous, current *Config) error {
+	store, err := newSecretStore(previous.SecretStore)
+	if err != nil || store == nil {
+		return err
+	}
+	currentRefs := make(map[string]bool)
+	for _, serverDetails := range current.Servers {
+		currentRefs[serverDetails.SecretsRef] = true
+		currentRefs[serverDetails.ServerId] = true
+	}
+	for _, serverDetails := range previous.Servers {
+		if serverDetails.SecretsRef == "" || currentRefs[serverDetails.SecretsRef] {
+			continue
+		}
+		log.Debug("Erasing the secrets of server ID:", serverDetails.ServerId)
+		if err = store.Erase(serverDetails.SecretsRef); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sets the secret store backend and moves the existing secrets into it.
+func SetSecretStore(details *SecretStoreDetails) error {
+	conf, err := readConf()
+	if err != nil {
+		return err
+	}
+	if _, err = newSecretStore(details); err != nil {
+		return err
+	}
+	previous, err := conf.Clone()
+	if err != nil {
+		return err
+	}
+	conf.SecretStore = details
+	if err = saveConfig(conf); err != nil {
+		return err
+	}
+	if previous.SecretStore.equals(details) {
+		return nil
+	}
+	// The secrets were moved out of the previous store, erase them.
+	return eraseRemovedSecrets(previous, &Config{})
+}
+
+// Returns the configured secret store backend details, or nil if the default file secret store is used.
+func GetSecretStoreDetails() (*SecretStoreDetails, error) {
+	conf, err := readConf()
+	if err != nil {
+		return nil, err
+	}
+	return conf.SecretStore, nil
+}
+
+func (serverDetails *ServerDetails) getSecrets() *ServerSecrets {
+	return &ServerSecrets{
+		Password:                serverDetails.Password,
+		AccessToken:             serverDetails.AccessToken,
+		SshPassphrase:           serverDetails.SshPassphrase,
+		RefreshToken:            serverDetails.RefreshToken,
+		ArtifactoryRefreshToken: serverDetails.ArtifactoryRefreshToken,
+	}
+}
+
+func (serverDetails *ServerDetails) setSecrets(secrets *ServerSecrets) {
+	serverDetails.Password = secrets.Password
+	serverDetails.AccessToken = secrets.AccessToken
+	serverDetails.SshPassphrase = secrets.SshPassphrase
+	serverDetails.RefreshToken = secrets.RefreshToken
+	serverDetails.ArtifactoryRefreshToken = secrets.ArtifactoryRefreshToken
+}
+
+// MemorySecretStore keeps the secrets in the memory of the current process, and they're gone when it exits.
+// It isn't registered by default. Register it using RegisterSecretStore.
+type MemorySecretStore struct {
+	secrets map[string]ServerSecrets
+	mutex   sync.Mutex
+}
+
+func NewMemorySecretStore() *MemorySecretStore {
+	return &MemorySecretStore{secrets: make(map[string]ServerSecrets)}
+}
+
+func (mss *MemorySecretStore) Get(ref string) (*ServerSecrets, error) {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	secrets := mss.secrets[ref]
+	return &secrets, nil
+}
+
+func (mss *MemorySecretStore) Store(ref string, secrets *ServerSecrets) error {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	mss.secrets[ref] = *secrets
+	return nil
+}
+
+func (mss *MemorySecretStore) Erase(ref string) error {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+	delete(mss.secrets, ref)
+	return nil
+}
+
+// CommandSecretStore delegates the secrets storage to an external credential helper executable.
+// The helper is invoked with one of the 'get', 'store' or 'erase' actions as its last argument,
+// reads a JSON request from stdin and writes a JSON response to stdout:
+// get - request: {"ref": "<ref>"}, response: {"secrets": {...}}
+// store - request: {"ref": "<ref>", "secrets": {...}}, no response
+// erase - request: {"ref": "<ref>"}, no response
+type CommandSecretStore struct {
+	executable string
+	args       []string
+}
+
+type secretHelperMessage struct {
+	Ref     string         `json:"ref,omitempty"`
+	Secrets *ServerSecrets `json:"secrets,omitempty"`
+}
+
+func NewCommandSecretStore(command string) (*CommandSecretStore, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errorutils.CheckErrorf("the command secret store requires a credential helper command")
+	}
+	return &CommandSecretStore{executable: fields[0], args: fields[1:]}, nil
+}
+
+func (css *CommandSecretStore) Get(ref string) (*ServerSecrets, error) {
+	output, err := css.run(secretHelperGetAction, &secretHelperMessage{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	response := &secretHelperMessage{}
+	if len(bytes.TrimSpace(output)) > 0 {
+		if err = json.Unmarshal(output, response); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse the credential helper response: %s", err.Error())
+		}
+	}
+	if response.Secrets == nil {
+		return &ServerSecrets{}, nil
+	}
+	return response.Secrets, nil
+}
+
+func (css *CommandSecretStore) Store(ref string, secrets *ServerSecrets) error {
+	_, err := css.run(secretHelperStoreAction, &secretHelperMessage{Ref: ref, Secrets: secrets})
+	return err
+}
+
+func (css *CommandSecretStore) Erase(ref string) error {
+	_, err := css.run(secretHelperEraseAction, &secretHelperMessage{Ref: ref})
+	return err
+}
+
+func (css *CommandSecretStore) run(action string, request *secretHelperMessage) ([]byte, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	cmd := exec.Command(css.executable, append(append([]string{}, css.args...), action)...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, errorutils.CheckErrorf("credential helper '%s %s' failed: %s %s", css.executable, action, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
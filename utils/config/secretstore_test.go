@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+const memorySecretStoreType = "memory"
+
+// The memory secret store used by the current process.
+var processMemorySecretStore = NewMemorySecretStore()
+
+// Registers the memory secret store for the duration of the test.
+func useMemorySecretStore(t *testing.T) {
+	assert.NoError(t, RegisterSecretStore(memorySecretStoreType, processMemorySecretStore))
+	t.Cleanup(func() {
+		UnregisterSecretStore(memorySecretStoreType)
+	})
+}
+
+func TestRegisterSecretStore(t *testing.T) {
+	useMemorySecretStore(t)
+	// The type is already registered
+	assert.Error(t, RegisterSecretStore(memorySecretStoreType, NewMemorySecretStore()))
+	// Reserved types
+	for _, storeType := range []string{"", FileSecretStoreType, CommandSecretStoreType} {
+		assert.Error(t, RegisterSecretStore(storeType, NewMemorySecretStore()))
+	}
+	assert.Error(t, RegisterSecretStore("other", nil))
+
+	store, err := newSecretStore(&SecretStoreDetails{Type: memorySecretStoreType})
+	assert.NoError(t, err)
+	assert.Same(t, processMemorySecretStore, store)
+	_, err = newSecretStore(&SecretStoreDetails{Type: "other"})
+	assert.Error(t, err)
+}
+
+func TestMemorySecretStoreNotPersisted(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	assert.NoError(t, saveConfig(createEncryptionTestConfig()))
+	assert.Error(t, SetSecretStore(&SecretStoreDetails{Type: memorySecretStoreType}))
+	details, err := GetSecretStoreDetails()
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+}
+
+func TestMemorySecretStore(t *testing.T) {
+	useMemorySecretStore(t)
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	expectedConfig := createEncryptionTestConfig()
+	assert.NoError(t, saveConfig(expectedConfig))
+	assert.NoError(t, SetSecretStore(&SecretStoreDetails{Type: memorySecretStoreType}))
+
+	// Ensure the config file holds only a reference to the secrets
+	actualConfig := readConfFromFile(t)
+	assert.Equal(t, memorySecretStoreType, actualConfig.SecretStore.Type)
+	assert.Equal(t, "test-server", actualConfig.Servers[0].SecretsRef)
+	assert.Equal(t, &ServerSecrets{}, actualConfig.Servers[0].getSecrets())
+	secrets, err := processMemorySecretStore.Get("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConfig.Servers[0].getSecrets(), secrets)
+
+	// Ensure the secrets are resolved when reading the config
+	serverDetails, err := GetSpecificConfig("test-server", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConfig.Servers[0].getSecrets(), serverDetails.getSecrets())
+
+	// Ensure the secrets are erased when the server is removed
+	assert.NoError(t, SaveServersConf([]*ServerDetails{}))
+	secrets, err = processMemorySecretStore.Get("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerSecrets{}, secrets)
+}
+
+func TestSecretStoreBackToFile(t *testing.T) {
+	useMemorySecretStore(t)
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+
+	expectedConfig := createEncryptionTestConfig()
+	assert.NoError(t, saveConfig(expectedConfig))
+	assert.NoError(t, SetSecretStore(&SecretStoreDetails{Type: memorySecretStoreType}))
+	assert.NoError(t, SetSecretStore(&SecretStoreDetails{Type: FileSecretStoreType}))
+
+	actualConfig := readConfFromFile(t)
+	assert.Empty(t, actualConfig.Servers[0].SecretsRef)
+	assert.Equal(t, expectedConfig.Servers[0].getSecrets(), actualConfig.Servers[0].getSecrets())
+	secrets, err := processMemorySecretStore.Get("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerSecrets{}, secrets)
+}
+
+func TestCommandSecretStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The credential helper script is a shell script.")
+	}
+	tempDir := t.TempDir()
+	storagePath := filepath.Join(tempDir, "secrets.json")
+	helperPath := filepath.Join(tempDir, "helper.sh")
+	// A credential helper saving the last stored request, and returning it on 'get'.
+	helperScript := `#!/bin/sh
+case "$1" in
+  store) cat > "` + storagePath + `" ;;
+  get) cat "` + storagePath + `" 2>/dev/null; cat > /dev/null ;;
+  erase) rm -f "` + storagePath + `" ;;
+  *) exit 1 ;;
+esac
+`
+	assert.NoError(t, os.WriteFile(helperPath, []byte(helperScript), 0700))
+
+	store, err := NewCommandSecretStore(helperPath)
+	assert.NoError(t, err)
+	expectedSecrets := &ServerSecrets{Password: "password", AccessToken: "token"}
+	assert.NoError(t, store.Store("server", expectedSecrets))
+	actualSecrets, err := store.Get("server")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSecrets, actualSecrets)
+
+	assert.NoError(t, store.Erase("server"))
+	actualSecrets, err = store.Get("server")
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerSecrets{}, actualSecrets)
+
+	_, err = NewCommandSecretStore("")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/access"
 	accessservices "github.com/jfrog/jfrog-client-go/access/services"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strconv"
 	"sync"
 	"time"
 
@@ -164,18 +165,19 @@ func writeNewArtifactoryTokens(serverConfiguration *ServerDetails, serverId, acc
 	serverConfiguration.SetAccessToken(accessToken)
 	serverConfiguration.SetArtifactoryRefreshToken(refreshToken)
 
-	// Get configurations list
-	configurations, err := GetAllServersConfigs()
+	// Read the config and save it right away, so that concurrent modifications by other processes are merged rather than overridden.
+	conf, err := readConf()
 	if err != nil {
 		return err
 	}
 
 	// Remove and get the server details from the configurations list
-	_, configurations = GetAndRemoveConfiguration(serverId, configurations)
+	_, conf.Servers = GetAndRemoveConfiguration(serverId, conf.Servers)
 
 	// Append the configuration to the configurations list
-	configurations = append(configurations, serverConfiguration)
-	return SaveServersConf(configurations)
+	conf.Servers = append(conf.Servers, serverConfiguration)
+	conf.Version = strconv.Itoa(coreutils.GetCliConfigVersion())
+	return saveConfig(conf)
 }
 
 func createTokensForConfig(serverDetails *ServerDetails, expirySeconds int) (auth.CreateTokenResponseData, error) {
@@ -43,6 +43,8 @@ const (
 	DependenciesDir    = "JFROG_CLI_DEPENDENCIES_DIR"
 	TransitiveDownload = "JFROG_CLI_TRANSITIVE_DOWNLOAD_EXPERIMENTAL"
 	FailNoOp           = "JFROG_CLI_FAIL_NO_OP"
+	Context            = "JFROG_CLI_CONTEXT"
+	OidcIdToken        = "JFROG_CLI_OIDC_ID_TOKEN"
 	CI                 = "CI"
 )
 
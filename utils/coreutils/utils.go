@@ -359,6 +359,16 @@ func GetJfrogConfigLockDir() (string, error) {
 	return filepath.Join(locksDirPath, configLockDirName), nil
 }
 
+// The config write lock is held only while writing the config file, and may therefore be acquired while holding the config lock.
+func GetJfrogConfigWriteLockDir() (string, error) {
+	configWriteLockDirName := "config-write"
+	locksDirPath, err := GetJfrogLocksDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(locksDirPath, configWriteLockDirName), nil
+}
+
 func GetJfrogPluginsLockDir() (string, error) {
 	pluginsLockDirName := "plugins"
 	locksDirPath, err := GetJfrogLocksDir()
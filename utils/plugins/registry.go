@@ -0,0 +1,613 @@
+package plugins
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The number of previous versions kept for each plugin, to allow rolling back.
+	MaxPluginBackups = 3
+
+	pluginBackupsDirName = "backups"
+	pluginStagingDirName = ".staging"
+)
+
+var (
+	pluginNameRegexp    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+	pluginVersionRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+_-]*$`)
+)
+
+type PluginVersion struct {
+	Version string `json:"version,omitempty"`
+	// The sha256 checksum of the installed artifact - the archive or the executable.
+	Sha256 string `json:"sha256,omitempty"`
+	// The sha256 checksum of the plugin's executable, used to verify the integrity of the installed plugin.
+	ExecutableSha256 string `json:"executableSha256,omitempty"`
+	// The local path or the Artifactory path the plugin was installed from.
+	Source      string    `json:"source,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+type InstalledPlugin struct {
+	Name string `json:"name"`
+	PluginVersion
+	// The previous versions available for rollback, from the newest to the oldest.
+	Backups []PluginVersion `json:"backups,omitempty"`
+}
+
+type InstallPluginParams struct {
+	Name    string
+	Version string
+	// The expected sha256 checksum of the plugin's artifact.
+	Sha256 string
+	// A base64 encoded ed25519 signature of the plugin's artifact, verified using the public key.
+	Signature string
+	// Path to a PEM encoded ed25519 public key.
+	PublicKeyPath string
+}
+
+// Installs a plugin from a local executable or archive, and activates it.
+// An archive should include the plugin's executable, either in its root or in a 'bin' directory, and optionally a 'resources' directory.
+// The artifact must match the provided sha256 checksum or signature. If a different version of the plugin is installed,
+// it is kept as a backup, and can be restored using RollbackPlugin.
+func InstallPlugin(artifactPath string, params InstallPluginParams) (*InstalledPlugin, error) {
+	if params.Sha256 == "" && params.Signature == "" {
+		return nil, errorutils.CheckErrorf("the sha256 checksum or the signature of plugin '%s' must be provided, to verify it before installing", params.Name)
+	}
+	return installPlugin(artifactPath, params, artifactPath)
+}
+
+// Downloads a plugin's executable or archive from Artifactory, and installs it similarly to InstallPlugin.
+// The downloaded artifact is verified locally, using the provided sha256 checksum or signature.
+func InstallPluginFromArtifactory(serverDetails *config.ServerDetails, artifactoryPath string, params InstallPluginParams) (installed *InstalledPlugin, err error) {
+	if params.Sha256 == "" && params.Signature == "" {
+		return nil, errorutils.CheckErrorf("the sha256 checksum or the signature of plugin '%s' must be provided, to verify it before installing", params.Name)
+	}
+	artifactoryPath = strings.TrimPrefix(artifactoryPath, "/")
+	servicesManager, err := utils.CreateServiceManager(serverDetails, -1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if params.Sha256 != "" {
+		// Avoid downloading an artifact which doesn't match the expected checksum.
+		var artifactSha256 string
+		if artifactSha256, err = getArtifactSha256(servicesManager, artifactoryPath); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(params.Sha256, artifactSha256) {
+			return nil, errorutils.CheckErrorf("the sha256 checksum of '%s' in Artifactory is %s, while %s was expected", artifactoryPath, artifactSha256, params.Sha256)
+		}
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := fileutils.RemoveTempDir(tempDir); err == nil {
+			err = e
+		}
+	}()
+	// Keep the artifact's name, since the archive format is determined by its extension.
+	artifactPath := filepath.Join(tempDir, filepath.Base(artifactoryPath))
+	log.Info("Downloading plugin", params.Name, "from", artifactoryPath+"...")
+	body, err := servicesManager.ReadRemoteFile(artifactoryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := body.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	if err = writeToFile(artifactPath, body); err != nil {
+		return nil, err
+	}
+	return installPlugin(artifactPath, params, serverDetails.ServerId+":"+artifactoryPath)
+}
+
+type artifactStorageInfo struct {
+	Checksums struct {
+		Sha256 string `json:"sha256"`
+	} `json:"checksums"`
+}
+
+func getArtifactSha256(servicesManager artifactory.ArtifactoryServicesManager, artifactoryPath string) (string, error) {
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	httpClientDetails := artDetails.CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(artDetails.GetUrl()+"api/storage/"+artifactoryPath, true, &httpClientDetails)
+	if err != nil {
+		return "", err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return "", err
+	}
+	storageInfo := new(artifactStorageInfo)
+	if err = json.Unmarshal(body, storageInfo); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if storageInfo.Checksums.Sha256 == "" {
+		return "", errorutils.CheckErrorf("no sha256 checksum was found for '%s' in Artifactory", artifactoryPath)
+	}
+	return storageInfo.Checksums.Sha256, nil
+}
+
+func writeToFile(path string, reader io.Reader) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	_, err = io.Copy(file, reader)
+	return errorutils.CheckError(err)
+}
+
+func installPlugin(artifactPath string, params InstallPluginParams, source string) (*InstalledPlugin, error) {
+	name := params.Name
+	if !pluginNameRegexp.MatchString(name) {
+		return nil, errorutils.CheckErrorf("invalid plugin name '%s'", name)
+	}
+	if !pluginVersionRegexp.MatchString(params.Version) {
+		return nil, errorutils.CheckErrorf("invalid version '%s' of plugin '%s'", params.Version, name)
+	}
+	artifactSha256, err := verifyPluginArtifact(artifactPath, params)
+	if err != nil {
+		return nil, err
+	}
+	installed := &InstalledPlugin{Name: name, PluginVersion: PluginVersion{Version: params.Version, Sha256: artifactSha256, Source: source, InstalledAt: time.Now().UTC()}}
+	err = runWithPluginsLock(func(pluginsConfig *PluginsV1) error {
+		if current := pluginsConfig.Plugins[name]; current != nil && current.Version == params.Version {
+			return errorutils.CheckErrorf("version %s of plugin '%s' is already installed", params.Version, name)
+		}
+		pluginDir, err := getPluginDir(name)
+		if err != nil {
+			return err
+		}
+		stagingDir := filepath.Join(pluginDir, pluginStagingDirName)
+		if err = os.RemoveAll(stagingDir); err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer func() {
+			// The staging directory is empty after a successful activation.
+			_ = os.RemoveAll(stagingDir)
+		}()
+		if err = stagePlugin(artifactPath, name, stagingDir); err != nil {
+			return err
+		}
+		if installed.ExecutableSha256, err = calcSha256(filepath.Join(stagingDir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(name))); err != nil {
+			return err
+		}
+		return activatePlugin(pluginsConfig, installed, stagingDir)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Plugin", name, "version", params.Version, "was installed successfully.")
+	return installed, nil
+}
+
+// Verifies the artifact's checksum and signature, and returns its sha256 checksum.
+func verifyPluginArtifact(artifactPath string, params InstallPluginParams) (string, error) {
+	artifactSha256, err := calcSha256(artifactPath)
+	if err != nil {
+		return "", err
+	}
+	if params.Sha256 != "" && !strings.EqualFold(params.Sha256, artifactSha256) {
+		return "", errorutils.CheckErrorf("the sha256 checksum of plugin '%s' is %s, while %s was expected", params.Name, artifactSha256, params.Sha256)
+	}
+	if params.Signature != "" {
+		if err = verifyPluginSignature(artifactPath, params); err != nil {
+			return "", err
+		}
+	}
+	return artifactSha256, nil
+}
+
+func verifyPluginSignature(artifactPath string, params InstallPluginParams) error {
+	if params.PublicKeyPath == "" {
+		return errorutils.CheckErrorf("a public key must be provided to verify the signature of plugin '%s'", params.Name)
+	}
+	publicKeyContent, err := os.ReadFile(params.PublicKeyPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	block, _ := pem.Decode(publicKeyContent)
+	if block == nil {
+		return errorutils.CheckErrorf("the public key file '%s' is not PEM encoded", params.PublicKeyPath)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	ed25519PublicKey, ok := publicKey.(ed25519.PublicKey)
+	if !ok {
+		return errorutils.CheckErrorf("the public key file '%s' does not contain an ed25519 public key", params.PublicKeyPath)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(params.Signature))
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	// An ed25519 signature is verified against the whole signed message.
+	content, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if !ed25519.Verify(ed25519PublicKey, content, signature) {
+		return errorutils.CheckErrorf("the signature of plugin '%s' is invalid", params.Name)
+	}
+	return nil
+}
+
+// Creates the plugin's 'bin' and 'resources' directories in the staging directory.
+func stagePlugin(artifactPath, name, stagingDir string) error {
+	executableName := GetLocalPluginExecutableName(name)
+	binDir := filepath.Join(stagingDir, coreutils.PluginsExecDirName)
+	if err := os.MkdirAll(binDir, 0777); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if !fileutils.IsSupportedArchive(artifactPath) {
+		return copyExecutable(artifactPath, filepath.Join(binDir, executableName))
+	}
+	extractionDir := filepath.Join(stagingDir, "extracted")
+	if err := fileutils.Unarchive(artifactPath, filepath.Base(artifactPath), extractionDir); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(extractionDir)
+	}()
+	var executablePath string
+	for _, candidate := range []string{filepath.Join(extractionDir, coreutils.PluginsExecDirName, executableName), filepath.Join(extractionDir, executableName)} {
+		exists, err := fileutils.IsFileExists(candidate, false)
+		if err != nil {
+			return err
+		}
+		if exists {
+			executablePath = candidate
+			break
+		}
+	}
+	if executablePath == "" {
+		return errorutils.CheckErrorf("the archive of plugin '%s' does not include the '%s' executable", name, executableName)
+	}
+	if err := copyExecutable(executablePath, filepath.Join(binDir, executableName)); err != nil {
+		return err
+	}
+	resourcesDir := filepath.Join(extractionDir, coreutils.PluginsResourcesDirName)
+	exists, err := fileutils.IsDirExists(resourcesDir, false)
+	if err != nil || !exists {
+		return err
+	}
+	return errorutils.CheckError(os.Rename(resourcesDir, filepath.Join(stagingDir, coreutils.PluginsResourcesDirName)))
+}
+
+func copyExecutable(sourcePath, executablePath string) (err error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := source.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	if err = writeToFile(executablePath, source); err != nil {
+		return err
+	}
+	return errorutils.CheckError(os.Chmod(executablePath, 0777))
+}
+
+// Replaces the active version of the plugin with the staged version, while backing up the active version.
+func activatePlugin(pluginsConfig *PluginsV1, installed *InstalledPlugin, stagingDir string) error {
+	pluginDir := filepath.Dir(stagingDir)
+	current := pluginsConfig.Plugins[installed.Name]
+	if current == nil {
+		current = &InstalledPlugin{Name: installed.Name}
+	}
+	isActive, err := fileutils.IsDirExists(filepath.Join(pluginDir, coreutils.PluginsExecDirName), false)
+	if err != nil {
+		return err
+	}
+	installed.Backups = current.Backups
+	if isActive {
+		if installed.Backups, err = backupActiveVersion(pluginDir, current); err != nil {
+			return err
+		}
+	}
+	if err = movePluginContent(stagingDir, pluginDir); err != nil {
+		if isActive {
+			// Restore the previously active version.
+			if e := movePluginContent(filepath.Join(pluginDir, pluginBackupsDirName, installed.Backups[0].Version), pluginDir); e != nil {
+				log.Error("Failed restoring the previous version of plugin", installed.Name+":", e.Error())
+			}
+		}
+		return err
+	}
+	// A backup of the installed version is replaced by the installed version.
+	if err = os.RemoveAll(filepath.Join(pluginDir, pluginBackupsDirName, installed.Version)); err != nil {
+		return errorutils.CheckError(err)
+	}
+	installed.Backups, err = pruneBackups(pluginDir, removePluginVersion(installed.Backups, installed.Version))
+	if err != nil {
+		return err
+	}
+	pluginsConfig.Plugins[installed.Name] = installed
+	return savePluginsConfig(pluginsConfig)
+}
+
+// Moves the active version of the plugin to the backups directory, and returns the backups list, starting with the active version.
+func backupActiveVersion(pluginDir string, current *InstalledPlugin) ([]PluginVersion, error) {
+	// Plugins installed before the registry have no version.
+	backup := current.PluginVersion
+	if backup.Version == "" {
+		backup.Version = "unknown"
+	}
+	backupDir := filepath.Join(pluginDir, pluginBackupsDirName, backup.Version)
+	if err := os.RemoveAll(backupDir); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err := movePluginContent(pluginDir, backupDir); err != nil {
+		return nil, err
+	}
+	return append([]PluginVersion{backup}, removePluginVersion(current.Backups, backup.Version)...), nil
+}
+
+func removePluginVersion(versions []PluginVersion, version string) []PluginVersion {
+	var result []PluginVersion
+	for _, pluginVersion := range versions {
+		if pluginVersion.Version != version {
+			result = append(result, pluginVersion)
+		}
+	}
+	return result
+}
+
+// Removes the backups exceeding MaxPluginBackups.
+func pruneBackups(pluginDir string, backups []PluginVersion) ([]PluginVersion, error) {
+	if len(backups) <= MaxPluginBackups {
+		return backups, nil
+	}
+	for _, backup := range backups[MaxPluginBackups:] {
+		if err := os.RemoveAll(filepath.Join(pluginDir, pluginBackupsDirName, backup.Version)); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	return backups[:MaxPluginBackups], nil
+}
+
+// Moves the plugin's 'bin' and 'resources' directories.
+func movePluginContent(sourceDir, targetDir string) error {
+	if err := os.MkdirAll(targetDir, 0777); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for _, dirName := range []string{coreutils.PluginsExecDirName, coreutils.PluginsResourcesDirName} {
+		exists, err := fileutils.IsDirExists(filepath.Join(sourceDir, dirName), false)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(targetDir, dirName)); err != nil {
+			return errorutils.CheckError(err)
+		}
+		if err = os.Rename(filepath.Join(sourceDir, dirName), filepath.Join(targetDir, dirName)); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+// Restores the previous version of the plugin. The active version is kept as a backup.
+func RollbackPlugin(name string) (restored *InstalledPlugin, err error) {
+	err = runWithPluginsLock(func(pluginsConfig *PluginsV1) error {
+		current := pluginsConfig.Plugins[name]
+		if current == nil || len(current.Backups) == 0 {
+			return errorutils.CheckErrorf("no previous version of plugin '%s' is available for rollback", name)
+		}
+		pluginDir, err := getPluginDir(name)
+		if err != nil {
+			return err
+		}
+		backupDir := filepath.Join(pluginDir, pluginBackupsDirName, current.Backups[0].Version)
+		restored = &InstalledPlugin{Name: name, PluginVersion: current.Backups[0]}
+		if restored.ExecutableSha256 != "" {
+			if err = verifyExecutable(filepath.Join(backupDir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(name)), restored); err != nil {
+				return err
+			}
+		}
+		// Move the restored version out of the backups directory, before the active version is moved into it.
+		restoredDir := filepath.Join(pluginDir, pluginStagingDirName)
+		if err = os.RemoveAll(restoredDir); err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer func() {
+			_ = os.RemoveAll(restoredDir)
+		}()
+		if err = movePluginContent(backupDir, restoredDir); err != nil {
+			return err
+		}
+		if err = os.RemoveAll(backupDir); err != nil {
+			return errorutils.CheckError(err)
+		}
+		if restored.Backups, err = backupActiveVersion(pluginDir, &InstalledPlugin{Name: name, PluginVersion: current.PluginVersion, Backups: current.Backups[1:]}); err != nil {
+			return err
+		}
+		if err = movePluginContent(restoredDir, pluginDir); err != nil {
+			return err
+		}
+		if restored.Backups, err = pruneBackups(pluginDir, restored.Backups); err != nil {
+			return err
+		}
+		pluginsConfig.Plugins[name] = restored
+		return savePluginsConfig(pluginsConfig)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Plugin", name, "was rolled back to version", restored.Version+".")
+	return restored, nil
+}
+
+// Returns the installed plugins, sorted by name.
+// Plugins which weren't installed using InstallPlugin are returned without a version.
+func GetInstalledPlugins() ([]InstalledPlugin, error) {
+	if err := CheckPluginsVersionAndConvertIfNeeded(); err != nil {
+		return nil, err
+	}
+	pluginsConfig, err := readPluginsConfig()
+	if err != nil {
+		return nil, err
+	}
+	pluginsDirContent, err := coreutils.GetPluginsDirContent()
+	if err != nil {
+		return nil, err
+	}
+	var installedPlugins []InstalledPlugin
+	for _, entry := range pluginsDirContent {
+		if !entry.IsDir() {
+			continue
+		}
+		if installed := pluginsConfig.Plugins[entry.Name()]; installed != nil {
+			installedPlugins = append(installedPlugins, *installed)
+		} else {
+			installedPlugins = append(installedPlugins, InstalledPlugin{Name: entry.Name()})
+		}
+	}
+	sort.Slice(installedPlugins, func(i, j int) bool {
+		return installedPlugins[i].Name < installedPlugins[j].Name
+	})
+	return installedPlugins, nil
+}
+
+// Verifies that the executable of an installed plugin wasn't modified since it was installed.
+func VerifyPluginIntegrity(name string) error {
+	pluginsConfig, err := readPluginsConfig()
+	if err != nil {
+		return err
+	}
+	installed := pluginsConfig.Plugins[name]
+	if installed == nil {
+		return errorutils.CheckErrorf("plugin '%s' was not installed using the plugins registry", name)
+	}
+	pluginDir, err := getPluginDir(name)
+	if err != nil {
+		return err
+	}
+	return verifyExecutable(filepath.Join(pluginDir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(name)), installed)
+}
+
+func verifyExecutable(executablePath string, installed *InstalledPlugin) error {
+	executableSha256, err := calcSha256(executablePath)
+	if err != nil {
+		return err
+	}
+	if executableSha256 != installed.ExecutableSha256 {
+		return errorutils.CheckErrorf("the executable of plugin '%s' version %s was modified since it was installed", installed.Name, installed.Version)
+	}
+	return nil
+}
+
+func calcSha256(filePath string) (string, error) {
+	details, err := fileutils.GetFileDetails(filePath, true)
+	if err != nil {
+		return "", err
+	}
+	return details.Checksum.Sha256, nil
+}
+
+func getPluginDir(name string) (string, error) {
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginsDir, name), nil
+}
+
+func readPluginsConfig() (*PluginsV1, error) {
+	content, err := getPluginsConfigFileContent()
+	if err != nil {
+		return nil, err
+	}
+	pluginsConfig := &PluginsV1{Version: coreutils.GetPluginsConfigVersion()}
+	if len(content) > 0 {
+		if err = json.Unmarshal(content, pluginsConfig); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	if pluginsConfig.Plugins == nil {
+		pluginsConfig.Plugins = make(map[string]*InstalledPlugin)
+	}
+	return pluginsConfig, nil
+}
+
+func savePluginsConfig(pluginsConfig *PluginsV1) error {
+	pluginsFilePath, err := getPluginsFilePath()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(pluginsConfig, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(pluginsFilePath, content, 0600))
+}
+
+// Runs the provided function on the plugins configuration, while locking the plugins directory for other threads and processes.
+func runWithPluginsLock(action func(pluginsConfig *PluginsV1) error) (err error) {
+	if err = CheckPluginsVersionAndConvertIfNeeded(); err != nil {
+		return
+	}
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(pluginsDir, 0777); err != nil {
+		return errorutils.CheckError(err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	lockDirPath, err := coreutils.GetJfrogPluginsLockDir()
+	if err != nil {
+		return
+	}
+	unlockFunc, err := lock.CreateLock(lockDirPath)
+	// Defer the lockFile.Unlock() function before throwing a possible error to avoid deadlock situations.
+	defer func() {
+		e := unlockFunc()
+		if err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+	pluginsConfig, err := readPluginsConfig()
+	if err != nil {
+		return
+	}
+	return action(pluginsConfig)
+}
@@ -0,0 +1,209 @@
+package plugins
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const registryPluginName = "test-plugin"
+
+func TestInstallUpgradeAndRollbackPlugin(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	assert.NoError(t, err)
+	executablePath := filepath.Join(pluginsDir, registryPluginName, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(registryPluginName))
+	resourcePath := filepath.Join(pluginsDir, registryPluginName, coreutils.PluginsResourcesDirName, "resource.txt")
+
+	// Install from an executable
+	executable, executableSha256 := createTestArtifact(t, GetLocalPluginExecutableName(registryPluginName), []byte("v1"))
+	_, err = InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: "1.0.0"})
+	assert.ErrorContains(t, err, "must be provided")
+	installed, err := InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Sha256: executableSha256})
+	assert.NoError(t, err)
+	assert.Equal(t, executableSha256, installed.Sha256)
+	assertFileContent(t, executablePath, "v1")
+	assert.NoError(t, VerifyPluginIntegrity(registryPluginName))
+
+	// Upgrade from an archive
+	archive, archiveSha256 := createTestArchive(t, map[string]string{
+		"bin/" + GetLocalPluginExecutableName(registryPluginName): "v2",
+		"resources/resource.txt":                                  "resource",
+	})
+	_, err = InstallPlugin(archive, InstallPluginParams{Name: registryPluginName, Version: "2.0.0", Sha256: executableSha256})
+	assert.ErrorContains(t, err, "sha256 checksum")
+	assertFileContent(t, executablePath, "v1")
+	installed, err = InstallPlugin(archive, InstallPluginParams{Name: registryPluginName, Version: "2.0.0", Sha256: archiveSha256})
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", installed.Version)
+	assert.Equal(t, []string{"1.0.0"}, getBackupVersions(installed))
+	assertFileContent(t, executablePath, "v2")
+	assertFileContent(t, resourcePath, "resource")
+	_, err = InstallPlugin(archive, InstallPluginParams{Name: registryPluginName, Version: "2.0.0", Sha256: archiveSha256})
+	assert.ErrorContains(t, err, "already installed")
+
+	installedPlugins, err := GetInstalledPlugins()
+	assert.NoError(t, err)
+	if assert.Len(t, installedPlugins, 1) {
+		assert.Equal(t, registryPluginName, installedPlugins[0].Name)
+		assert.Equal(t, "2.0.0", installedPlugins[0].Version)
+		assert.Equal(t, archive, installedPlugins[0].Source)
+	}
+
+	// Modified executables are detected
+	assert.NoError(t, os.WriteFile(executablePath, []byte("modified"), 0777))
+	assert.Error(t, VerifyPluginIntegrity(registryPluginName))
+
+	// Rollback keeps the active version as a backup
+	restored, err := RollbackPlugin(registryPluginName)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", restored.Version)
+	assert.Equal(t, []string{"2.0.0"}, getBackupVersions(restored))
+	assertFileContent(t, executablePath, "v1")
+	assert.NoFileExists(t, resourcePath)
+	assert.NoError(t, VerifyPluginIntegrity(registryPluginName))
+	assert.NoDirExists(t, filepath.Join(pluginsDir, registryPluginName, pluginBackupsDirName, "1.0.0"))
+	// The backed up version was modified
+	_, err = RollbackPlugin(registryPluginName)
+	assert.ErrorContains(t, err, "modified")
+
+	// Reinstalling a backed up version replaces its backup
+	installed, err = InstallPlugin(archive, InstallPluginParams{Name: registryPluginName, Version: "2.0.0", Sha256: archiveSha256})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0"}, getBackupVersions(installed))
+	assertFileContent(t, executablePath, "v2")
+	assert.NoDirExists(t, filepath.Join(pluginsDir, registryPluginName, pluginBackupsDirName, "2.0.0"))
+	assert.NoError(t, VerifyPluginIntegrity(registryPluginName))
+}
+
+func TestInstallPluginBackupsLimit(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	var installed *InstalledPlugin
+	for i := 1; i <= MaxPluginBackups+2; i++ {
+		executable, executableSha256 := createTestArtifact(t, registryPluginName, []byte(fmt.Sprintf("v%d", i)))
+		var err error
+		installed, err = InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: fmt.Sprintf("%d.0.0", i), Sha256: executableSha256})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"4.0.0", "3.0.0", "2.0.0"}, getBackupVersions(installed))
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(pluginsDir, registryPluginName, pluginBackupsDirName, "1.0.0"))
+	assert.DirExists(t, filepath.Join(pluginsDir, registryPluginName, pluginBackupsDirName, "2.0.0"))
+}
+
+func TestInstallPluginSignature(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+	publicKeyPath := filepath.Join(t.TempDir(), "public.pem")
+	assert.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600))
+
+	content := []byte("signed")
+	executable, _ := createTestArtifact(t, registryPluginName, content)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+	invalidSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("other")))
+
+	_, err = InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Signature: signature})
+	assert.ErrorContains(t, err, "public key")
+	_, err = InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Signature: invalidSignature, PublicKeyPath: publicKeyPath})
+	assert.ErrorContains(t, err, "signature")
+	_, err = InstallPlugin(executable, InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Signature: signature, PublicKeyPath: publicKeyPath})
+	assert.NoError(t, err)
+}
+
+func TestInstallPluginFromArtifactory(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	content := []byte("from-artifactory")
+	checksum := sha256.Sum256(content)
+	artifactSha256 := hex.EncodeToString(checksum[:])
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/storage/plugins-local/test-plugin/1.0.0/test-plugin":
+			_, err := fmt.Fprintf(w, `{"checksums":{"sha256":"%s"}}`, artifactSha256)
+			assert.NoError(t, err)
+		case "/artifactory/plugins-local/test-plugin/1.0.0/test-plugin":
+			_, err := w.Write(content)
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	serverDetails := &config.ServerDetails{ServerId: "test", ArtifactoryUrl: ts.URL + "/artifactory/"}
+
+	_, err := InstallPluginFromArtifactory(serverDetails, "plugins-local/test-plugin/1.0.0/test-plugin", InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Sha256: "0000"})
+	assert.ErrorContains(t, err, "sha256 checksum")
+	_, err = InstallPluginFromArtifactory(serverDetails, "plugins-local/test-plugin/1.0.0/test-plugin", InstallPluginParams{Name: registryPluginName, Version: "1.0.0"})
+	assert.ErrorContains(t, err, "must be provided")
+	installed, err := InstallPluginFromArtifactory(serverDetails, "/plugins-local/test-plugin/1.0.0/test-plugin", InstallPluginParams{Name: registryPluginName, Version: "1.0.0", Sha256: artifactSha256})
+	assert.NoError(t, err)
+	assert.Equal(t, artifactSha256, installed.Sha256)
+	assert.Equal(t, "test:plugins-local/test-plugin/1.0.0/test-plugin", installed.Source)
+	assert.NoError(t, VerifyPluginIntegrity(registryPluginName))
+}
+
+func createTestArtifact(t *testing.T, name string, content []byte) (path, sha256Checksum string) {
+	path = filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, content, 0700))
+	checksum := sha256.Sum256(content)
+	return path, hex.EncodeToString(checksum[:])
+}
+
+func createTestArchive(t *testing.T, files map[string]string) (path, sha256Checksum string) {
+	path = filepath.Join(t.TempDir(), "plugin.tar.gz")
+	archiveFile, err := os.Create(path)
+	assert.NoError(t, err)
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0700, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tarWriter.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	assert.NoError(t, archiveFile.Close())
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	checksum := sha256.Sum256(content)
+	return path, hex.EncodeToString(checksum[:])
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func getBackupVersions(installed *InstalledPlugin) (versions []string) {
+	for _, backup := range installed.Backups {
+		versions = append(versions, backup.Version)
+	}
+	return
+}
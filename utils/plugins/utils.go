@@ -24,6 +24,8 @@ func init() {
 
 type PluginsV1 struct {
 	Version int `json:"version,omitempty"`
+	// The plugins installed using InstallPlugin, by name.
+	Plugins map[string]*InstalledPlugin `json:"plugins,omitempty"`
 }
 
 // CheckPluginsVersionAndConvertIfNeeded In case the latest plugin's layout version isn't match to the local plugins hierarchy at '.jfrog/plugins' -